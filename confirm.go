@@ -0,0 +1,234 @@
+package huhtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+)
+
+// button is a single button of a confirm field, as it was rendered.
+type button struct {
+	label string
+
+	// focused is true if the button is the one that's currently selected
+	focused bool
+
+	// start and end are the columns the button is rendered in, end is exclusive
+	start int
+	end   int
+}
+
+// buttonStyles maps the styles that huh's themes render the buttons of a confirm field with to whether they belong
+// to the focused button, see screen.fieldStyles for what a style looks like. The focused button only stands out
+// by its colours, so we render the buttons of every theme in every color profile to find out what they look like.
+//
+// Every combination of theme and color profile gets its own map, as colours that are used by the focused button of
+// one theme may be used by the blurred button of another. Styles that are used by both buttons are left out.
+var buttonStyles = sync.OnceValue(func() []map[string]bool {
+	themes := []func() *huh.Theme{huh.ThemeBase, huh.ThemeCharm, huh.ThemeDracula, huh.ThemeBase16, huh.ThemeCatppuccin}
+	profiles := []termenv.Profile{termenv.ANSI, termenv.ANSI256, termenv.TrueColor}
+
+	var result []map[string]bool
+
+	for _, theme := range themes {
+		for _, profile := range profiles {
+			for _, dark := range []bool{true, false} {
+				renderer := lipgloss.NewRenderer(io.Discard)
+				renderer.SetColorProfile(profile)
+				renderer.SetHasDarkBackground(dark)
+
+				styles := theme().Focused
+				focused := renderedStyles(styles.FocusedButton.Renderer(renderer))
+				blurred := renderedStyles(styles.BlurredButton.Renderer(renderer))
+
+				known := make(map[string]bool)
+
+				for _, style := range focused {
+					known[style] = true
+				}
+
+				for _, style := range blurred {
+					known[style] = false
+				}
+
+				// Unstyled characters are never part of a button
+				for _, style := range append(slices.Clone(focused), "") {
+					if slices.Contains(blurred, style) || style == "" {
+						delete(known, style)
+					}
+				}
+
+				result = append(result, known)
+			}
+		}
+	}
+
+	return result
+})
+
+// renderedStyles returns the styles of the characters that the given style renders a button with.
+func renderedStyles(style lipgloss.Style) []string {
+	rendered := newScreen()
+	_, _ = rendered.Write([]byte(style.Render("x")))
+
+	return rendered.styles[0]
+}
+
+// parseButtons reads the buttons from a line of a confirm field using the styles of its characters, a button
+// consists of adjacent characters that have one of the known styles of a focused or a blurred button, see buttonStyles.
+func parseButtons(line string, styles []string, known map[string]bool) []button {
+	var result []button
+
+	// current is the button being read and its characters, it's nil between buttons
+	var current *button
+	var label []rune
+
+	// finish adds the button being read to the result
+	finish := func() {
+		if current != nil {
+			current.label = strings.TrimSpace(string(label))
+			result = append(result, *current)
+		}
+
+		current = nil
+		label = nil
+	}
+
+	for index, character := range []rune(line) {
+		var style string
+		if index < len(styles) {
+			style = styles[index]
+		}
+
+		focused, ok := known[style]
+		if !ok {
+			finish()
+			continue
+		}
+
+		if current != nil && current.focused != focused {
+			finish()
+		}
+
+		if current == nil {
+			current = &button{focused: focused, start: index}
+		}
+
+		label = append(label, character)
+		current.end = index + 1
+	}
+
+	finish()
+
+	return result
+}
+
+// findButtons returns the buttons of a confirm field, which are rendered on the last line of the field or on the
+// title line of an inline field. It returns false if they can't be found, which is the case if the field is rendered
+// without colours or with a custom theme.
+//
+// Only a single affirmative button is rendered if the negative label is empty, but the colours of one button may
+// also be the colours of a theme that isn't used. Finding both buttons is therefore preferred over finding one.
+func findButtons(field fieldView) ([]button, bool) {
+	var single []button
+
+	for _, known := range buttonStyles() {
+		for index := len(field.lines) - 1; index >= 0; index-- {
+			var styles []string
+			if index < len(field.styles) {
+				styles = field.styles[index]
+			}
+
+			buttons := parseButtons(field.lines[index], styles, known)
+			if len(buttons) == 0 {
+				continue
+			}
+
+			if slices.ContainsFunc(buttons, func(b button) bool { return b.label == "" }) {
+				break
+			}
+
+			switch {
+			case len(buttons) == 2 && buttons[0].focused != buttons[1].focused:
+				return buttons, true
+			case len(buttons) == 1 && buttons[0].focused && single == nil:
+				single = withUnstyledButton(field.lines[index], index == 0, buttons[0])
+			}
+
+			break
+		}
+	}
+
+	return single, single != nil
+}
+
+// withUnstyledButton returns the focused button along with the blurred button next to it on the given line, for
+// themes that don't style blurred buttons on some backgrounds. The title is rendered in front of the buttons of an
+// inline field, so on the title line only a button after the focused one is looked for.
+func withUnstyledButton(line string, title bool, focused button) []button {
+	runes := []rune(line)
+
+	if after := strings.TrimSpace(string(runes[focused.end:])); after != "" {
+		return []button{focused, {label: after}}
+	}
+
+	before := strings.TrimSpace(strings.TrimPrefix(string(runes[:focused.start]), focusedBorder))
+	if title || before == "" {
+		return []button{focused}
+	}
+
+	return []button{{label: before}, focused}
+}
+
+// errNoNegativeButton is returned by resolveConfirm if a negative answer is given to a confirm field that only
+// renders an affirmative button, which happens if the negative label is empty.
+var errNoNegativeButton = errors.New("confirm has no negative button")
+
+// errFocusGuessed is returned by resolveConfirm along with its input if the focused button of a confirm field can't be
+// read from the screen, in which case the input is only right if the negative button is focused.
+var errFocusGuessed = errors.New("the focused button can't be read from the screen, assuming that the negative button is focused")
+
+// resolveConfirm turns a ConfirmResponse into the input that selects the matching button of a confirm field, see
+// findButtons. Toggling moves the focus to the other button, so we only toggle if the wanted button isn't focused yet.
+//
+// If the focused button can't be read from the screen, like when a form is rendered without colours, we assume
+// that the negative button is focused as that's huh's default. The input is returned along with errFocusGuessed
+// then, as the answer is wrong if the confirm's value starts out as true.
+func resolveConfirm(answer string, field fieldView) (string, error) {
+	buttons, ok := findButtons(field)
+
+	switch {
+	case !ok:
+		if ConfirmResponse(answer) == ConfirmAffirm {
+			return arrowRight, errFocusGuessed
+		}
+
+		return "", errFocusGuessed
+
+	case len(buttons) == 1:
+		if ConfirmResponse(answer) == ConfirmNegative {
+			return "", fmt.Errorf("%w, only %q is available", errNoNegativeButton, buttons[0].label)
+		}
+
+		return "", nil
+	}
+
+	// The affirmative button is rendered first
+	wanted := buttons[0]
+	if ConfirmResponse(answer) == ConfirmNegative {
+		wanted = buttons[1]
+	}
+
+	if wanted.focused {
+		return "", nil
+	}
+
+	return arrowRight, nil
+}
@@ -0,0 +1,203 @@
+package huhtest
+
+import (
+	"io"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	"github.com/muesli/termenv"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// renderButtons renders a confirm field with the buttons of the given theme in colour and returns what it looks like,
+// the focused argument decides which button is focused.
+func renderButtons(t *testing.T, theme *huh.Theme, affirmative string, negative string, focused ConfirmResponse, inline bool) fieldView {
+	t.Helper()
+
+	renderer := lipgloss.NewRenderer(io.Discard)
+	renderer.SetColorProfile(termenv.TrueColor)
+
+	affirmativeStyle := theme.Focused.FocusedButton.Renderer(renderer)
+	negativeStyle := theme.Focused.BlurredButton.Renderer(renderer)
+
+	if focused == ConfirmNegative {
+		affirmativeStyle, negativeStyle = negativeStyle, affirmativeStyle
+	}
+
+	buttons := affirmativeStyle.Render(affirmative)
+	if negative != "" {
+		buttons = lipgloss.JoinHorizontal(lipgloss.Center, buttons, negativeStyle.Render(negative))
+	}
+
+	output := "┃ Title?\r\n┃\r\n┃ " + buttons
+	if inline {
+		output = "┃ Title? " + buttons
+	}
+
+	result := newScreen()
+	_, _ = result.Write([]byte(output))
+
+	lines := result.lines()
+
+	return fieldView{lines: lines, styles: result.fieldStyles(0, len(lines))}
+}
+
+func TestFindButtons_ReturnsRenderedButtons(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field fieldView
+
+		expected []button
+	}{
+		"negative focused": {
+			field:    renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmNegative, false),
+			expected: []button{{label: "Yes"}, {label: "No", focused: true}},
+		},
+		"affirmative focused": {
+			field:    renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmAffirm, false),
+			expected: []button{{label: "Yes", focused: true}, {label: "No"}},
+		},
+		"custom labels": {
+			field:    renderButtons(t, huh.ThemeDracula(), "Sure", "No thanks", ConfirmAffirm, false),
+			expected: []button{{label: "Sure", focused: true}, {label: "No thanks"}},
+		},
+		"inline": {
+			field:    renderButtons(t, huh.ThemeBase(), "Yes", "No", ConfirmNegative, true),
+			expected: []button{{label: "Yes"}, {label: "No", focused: true}},
+		},
+		"only affirmative": {
+			field:    renderButtons(t, huh.ThemeCatppuccin(), "Ok", "", ConfirmAffirm, false),
+			expected: []button{{label: "Ok", focused: true}},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, ok := findButtons(testData.field)
+
+			// Assert
+			require.True(t, ok)
+
+			// The columns depend on the padding of the theme
+			for index := range result {
+				result[index].start, result[index].end = 0, 0
+			}
+
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestFindButtons_ReturnsFalseWithoutColours(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := fieldView{lines: []string{"┃ Title?", "┃", "┃   Yes     No"}}
+
+	// Act
+	result, ok := findButtons(field)
+
+	// Assert
+	assert.False(t, ok)
+	assert.Empty(t, result)
+}
+
+func TestResolveConfirm_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		answer ConfirmResponse
+		field  fieldView
+
+		expected string
+	}{
+		"affirm with negative focused": {
+			answer:   ConfirmAffirm,
+			field:    renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmNegative, false),
+			expected: "<right>",
+		},
+		"affirm with affirmative focused": {
+			answer:   ConfirmAffirm,
+			field:    renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmAffirm, false),
+			expected: "",
+		},
+		"negative with affirmative focused": {
+			answer:   ConfirmNegative,
+			field:    renderButtons(t, huh.ThemeBase16(), "Sure", "Nope", ConfirmAffirm, true),
+			expected: "<right>",
+		},
+		"negative with negative focused": {
+			answer:   ConfirmNegative,
+			field:    renderButtons(t, huh.ThemeBase16(), "Sure", "Nope", ConfirmNegative, true),
+			expected: "",
+		},
+		"affirm with only affirmative": {
+			answer:   ConfirmAffirm,
+			field:    renderButtons(t, huh.ThemeCharm(), "Ok", "", ConfirmAffirm, false),
+			expected: "",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := resolveConfirm(string(testData.answer), testData.field)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestResolveConfirm_ReturnsGuessAndErrorWithoutColours(t *testing.T) {
+	t.Parallel()
+	tests := map[string]struct {
+		answer ConfirmResponse
+
+		expected string
+	}{
+		"affirm": {
+			answer:   ConfirmAffirm,
+			expected: "<right>",
+		},
+		"negative": {
+			answer:   ConfirmNegative,
+			expected: "",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			field := fieldView{lines: []string{"┃ Title?", "┃", "┃   Yes     No"}}
+
+			// Act
+			result, err := resolveConfirm(string(testData.answer), field)
+
+			// Assert
+			require.ErrorIs(t, err, errFocusGuessed)
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestResolveConfirm_ReturnsErrorOnMissingNegativeButton(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := renderButtons(t, huh.ThemeCharm(), "Ok", "", ConfirmAffirm, false)
+
+	// Act
+	result, err := resolveConfirm(string(ConfirmNegative), field)
+
+	// Assert
+	require.ErrorIs(t, err, errNoNegativeButton)
+	assert.EqualError(t, err, `confirm has no negative button, only "Ok" is available`)
+	assert.Empty(t, result)
+}
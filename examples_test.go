@@ -55,6 +55,7 @@ func ExampleNewResponder() {
 	stdin, stdout, cancel := NewResponder().
 		AddResponse("How Are You Feeling?", "Great").
 		AddConfirm("Are you ready?", ConfirmAffirm).
+		GuessConfirms().
 		AddMultiSelect("activities", []int{1, 2, 4}).
 		AddSelect("Have you slept well", 2).
 		Start(t, 1*time.Second)
@@ -78,3 +79,30 @@ func ExampleNewResponder() {
 	// Have you slept well? ok
 	// What are your favourite activities? sleep, boat, fly
 }
+
+func ExampleRunForm() {
+	// Arrange
+	var nameAnswer string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&nameAnswer),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher")
+
+	// Act
+	err := RunForm(t, myForm, responder, WithTimeout(1*time.Second))
+
+	// Assert
+	require.NoError(t, err)
+
+	fmt.Println("What's your name?", nameAnswer)
+
+	// Output:
+	// What's your name? Gopher
+}
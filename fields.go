@@ -0,0 +1,571 @@
+package huhtest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// labelSeparator is used to store multiple labels in a single answer, a newline can never be part of a
+// rendered option so it's safe to use.
+const labelSeparator = "\n"
+
+// option is a single option of a select or multi-select field, as it was rendered.
+type option struct {
+	label string
+
+	// cursor is true if the option is the one currently hovered
+	cursor bool
+
+	// selected is true if the option has been toggled in a multi-select
+	selected bool
+}
+
+var (
+	// selectedPrefixes are rendered in front of toggled options in a multi-select, depending on the theme
+	selectedPrefixes = []string{"✓ ", "[•] "}
+
+	// unselectedPrefixes are rendered in front of untoggled options in a multi-select, depending on the theme
+	unselectedPrefixes = []string{"• ", "[ ] "}
+)
+
+// parseOptions reads the options from the lines of a select or multi-select field. The first line is expected
+// to be the title, the lines that follow are options if they start with a selector or the indentation of one.
+func parseOptions(field []string) []option {
+	var options []option
+
+	for _, line := range fieldBody(field) {
+		var result option
+
+		switch {
+		case strings.HasPrefix(line, "> "):
+			result.cursor = true
+		case strings.HasPrefix(line, "  "):
+		default:
+			continue
+		}
+
+		result.label = line[2:]
+
+		for _, prefix := range selectedPrefixes {
+			if label, ok := strings.CutPrefix(result.label, prefix); ok {
+				result.label = label
+				result.selected = true
+			}
+		}
+
+		for _, prefix := range unselectedPrefixes {
+			result.label = strings.TrimPrefix(result.label, prefix)
+		}
+
+		options = append(options, result)
+	}
+
+	return options
+}
+
+// loadingIndicator is rendered instead of the options of a select or multi-select while they're loaded using
+// OptionsFunc, behind a frame of loadingSpinner
+const loadingIndicator = " Loading..."
+
+// loadingSpinner contains the frames of the spinner in front of loadingIndicator
+var loadingSpinner = []string{"|", "/", "-", "\\"}
+
+// isLoading returns true if the lines belong to a select or multi-select that's loading its options, which can't be
+// answered until they're shown.
+func isLoading(field []string) bool {
+	for _, line := range fieldBody(field) {
+		if frame, ok := strings.CutSuffix(line, loadingIndicator); ok && slices.Contains(loadingSpinner, frame) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// prevIndicator and nextIndicator surround the option under the cursor of an inline select, which is rendered
+// on the line of the title and is the only option that's shown
+const (
+	prevIndicator = "← "
+	nextIndicator = " →"
+)
+
+// errInlineSelect is returned if an answer needs to read the options of an inline select, which only shows one
+var errInlineSelect = errors.New("options of an inline select can't be read, pick an option by its index instead")
+
+// isInlineSelect returns true if the lines belong to an inline select, which renders the title and the option under
+// the cursor on a single line between indicators that can be used to move left and right.
+func isInlineSelect(field []string) bool {
+	return len(field) > 0 && len(parseOptions(field)) == 0 && strings.HasSuffix(field[len(field)-1], nextIndicator)
+}
+
+// focusedFieldType returns the type of field that the view looks like. Inputs are told apart from text fields by
+// their inputPrompt, so a field that isn't recognised is assumed to be a text field. That includes confirm fields of
+// which the buttons can't be found, see findButtons.
+func focusedFieldType(field fieldView) fieldType {
+	if field.note {
+		return fieldNote
+	}
+
+	if _, ok := findButtons(field); ok {
+		return fieldConfirm
+	}
+
+	switch {
+	case isMultiSelect(field.lines):
+		return fieldMultiSelect
+	case isInlineSelect(field.lines) || len(parseOptions(field.lines)) > 1:
+		return fieldSelect
+	}
+
+	if _, ok := inputValue(field.lines); ok {
+		return fieldInput
+	}
+
+	return fieldText
+}
+
+// isMultiSelect returns true if the lines belong to a multi-select, of which every option starts with one of the
+// selectedPrefixes or unselectedPrefixes.
+func isMultiSelect(field []string) bool {
+	var found bool
+
+	for _, line := range fieldBody(field) {
+		label, ok := strings.CutPrefix(line, "> ")
+		if !ok {
+			label, ok = strings.CutPrefix(line, "  ")
+		}
+
+		if !ok {
+			continue
+		}
+
+		hasPrefix := func(prefix string) bool { return strings.HasPrefix(label, prefix) }
+		if !slices.ContainsFunc(selectedPrefixes, hasPrefix) && !slices.ContainsFunc(unselectedPrefixes, hasPrefix) {
+			return false
+		}
+
+		found = true
+	}
+
+	return found
+}
+
+// errOptionNotFound is returned if a label is not among the rendered options
+var errOptionNotFound = errors.New("option not found")
+
+// findOptions returns the indexes of the given labels in the options and the index of the cursor. It returns
+// errOptionNotFound listing all available labels if any of them are missing.
+func findOptions(options []option, labels ...string) ([]int, int, error) {
+	indexes := make([]int, 0, len(labels))
+	cursor := 0
+
+	available := make([]string, len(options))
+
+	for index, option := range options {
+		available[index] = fmt.Sprintf("%q", option.label)
+
+		if option.cursor {
+			cursor = index
+		}
+	}
+
+	for _, label := range labels {
+		index := slices.IndexFunc(options, func(option option) bool { return option.label == label })
+		if index == -1 {
+			return nil, 0, fmt.Errorf("%q: %w, available options are %s", label, errOptionNotFound, strings.Join(available, ", "))
+		}
+
+		indexes = append(indexes, index)
+	}
+
+	return indexes, cursor, nil
+}
+
+// navigate returns the arrow keys needed to move from one option to another.
+func navigate(from int, to int) string {
+	if to < from {
+		return strings.Repeat(arrowUp, from-to)
+	}
+
+	return strings.Repeat(arrowDown, to-from)
+}
+
+// resolveSelectLabel turns a label into the arrow keys to move from the cursor to the option with that label.
+func resolveSelectLabel(label string, field fieldView) (string, error) {
+	if isInlineSelect(field.lines) {
+		return "", fmt.Errorf("%q: %w", label, errInlineSelect)
+	}
+
+	indexes, cursor, err := findOptions(parseOptions(field.lines), label)
+	if err != nil {
+		return "", err
+	}
+
+	return navigate(cursor, indexes[0]), nil
+}
+
+// pickLabels returns the indexes of the options with the labels separated by labelSeparator in the answer.
+func pickLabels(answer string, options []option) ([]int, error) {
+	var labels []string
+	if answer != "" {
+		labels = strings.Split(answer, labelSeparator)
+	}
+
+	indexes, _, err := findOptions(options, labels...)
+
+	return indexes, err
+}
+
+// errOptionOutOfRange is returned if an index is beyond the rendered options, which are all options of a field unless
+// its Height hides some of them
+var errOptionOutOfRange = errors.New("option out of range")
+
+// joinIndexes stores option indexes in a single answer, separated by labelSeparator.
+func joinIndexes(indexes []int) string {
+	result := make([]string, len(indexes))
+
+	for index, option := range indexes {
+		result[index] = strconv.Itoa(option)
+	}
+
+	return strings.Join(result, labelSeparator)
+}
+
+// findIndexes parses indexes stored by joinIndexes and returns them along with the index of the cursor. It returns
+// errOptionOutOfRange if any of them are beyond the options. A multi-select starts on its first option, so the
+// options that it shows are the first ones. The ones that its Height hides aren't known, as huh doesn't show how many
+// there are, so they can't be picked by index.
+//
+// If no options could be read, like when a form is answered line by line, the cursor is assumed to be on the
+// first of enough unselected options.
+func findIndexes(answer string, options []option) ([]int, []option, int, error) {
+	var indexes []int
+
+	if answer != "" {
+		for _, value := range strings.Split(answer, labelSeparator) {
+			index, err := strconv.Atoi(value)
+			if err != nil {
+				return nil, nil, 0, err
+			}
+
+			indexes = append(indexes, index)
+		}
+	}
+
+	if len(options) == 0 && len(indexes) > 0 {
+		return indexes, make([]option, slices.Max(indexes)+1), 0, nil
+	}
+
+	for _, index := range indexes {
+		if index < 0 || index >= len(options) {
+			return nil, nil, 0, fmt.Errorf("%d: %w, %d options are shown", index, errOptionOutOfRange, len(options))
+		}
+	}
+
+	cursor := max(slices.IndexFunc(options, func(option option) bool { return option.cursor }), 0)
+
+	return indexes, options, cursor, nil
+}
+
+// resolveSelectIndex turns an option index stored by joinIndexes into the arrow keys to move to that option. A
+// select with a Height only shows some of its options and scrolls to the option of the value that it's bound to, so
+// where the shown options are in the full list isn't known. The cursor is therefore moved to the first option and
+// then down, or to the right in an inline select, which only shows the option under the cursor.
+func resolveSelectIndex(answer string, field fieldView) (string, error) {
+	index, err := strconv.Atoi(answer)
+	if err != nil {
+		return "", err
+	}
+
+	if index < 0 {
+		return "", fmt.Errorf("%d: %w", index, errOptionOutOfRange)
+	}
+
+	if isInlineSelect(field.lines) {
+		return listStart + strings.Repeat(arrowRight, index), nil
+	}
+
+	// If no options could be read, like when a form is answered line by line, the cursor is assumed to be on the
+	// first option.
+	if len(parseOptions(field.lines)) == 0 {
+		return strings.Repeat(arrowDown, index), nil
+	}
+
+	return listStart + strings.Repeat(arrowDown, index), nil
+}
+
+// pickIndexes returns the option indexes stored by joinIndexes in the answer, see findIndexes.
+func pickIndexes(answer string, options []option) ([]int, error) {
+	indexes, _, _, err := findIndexes(answer, options)
+
+	return indexes, err
+}
+
+// pickAll returns the indexes of all options, the answer is ignored.
+func pickAll(_ string, options []option) ([]int, error) {
+	result := make([]int, len(options))
+
+	for index := range options {
+		result[index] = index
+	}
+
+	return result, nil
+}
+
+// pickNone returns no indexes, so that every option ends up deselected. The answer is ignored.
+func pickNone(string, []option) ([]int, error) {
+	return nil, nil
+}
+
+// toggleOptions returns the keys required to move from the cursor through the options and toggle them, so that
+// exactly the options with the given indexes end up selected. Options that shouldn't be selected are deselected
+// first, so that a limit on the amount of selected options doesn't get in the way.
+func toggleOptions(options []option, cursor int, indexes []int) string {
+	var result strings.Builder
+
+	for _, index := range toggleOrder(options, indexes) {
+		result.WriteString(navigate(cursor, index))
+		result.WriteString(selectOption)
+
+		cursor = index
+	}
+
+	return result.String()
+}
+
+// toggleOrder returns the indexes of the options that have to be toggled so that exactly the options with the given
+// indexes end up selected, the ones that have to be deselected come first.
+func toggleOrder(options []option, indexes []int) []int {
+	var result []int
+
+	for _, selected := range []bool{true, false} {
+		for index, option := range options {
+			if option.selected == selected && slices.Contains(indexes, index) != selected {
+				result = append(result, index)
+			}
+		}
+	}
+
+	return result
+}
+
+// errSelectionLimit is returned if an option of a multi-select couldn't be selected, which happens if the field
+// already has as many options selected as its Limit allows.
+var errSelectionLimit = errors.New("option can't be selected, the field has reached its limit")
+
+// toggleCheck selects exactly the picked options of a multi-select, one option at a time. Huh ignores toggles
+// beyond the Limit of the field without rendering anything, and the limit isn't shown either. That's why the cursor
+// is moved to a neighbour right after toggling an option, once the cursor shows up there the toggle has been handled
+// and we can check whether it worked.
+type toggleCheck struct {
+	answer string
+	pick   func(answer string, options []option) ([]int, error)
+
+	// picked are the indexes of the options that should end up selected, toggles are the ones that still have to
+	// be toggled. Both are set once the options are rendered.
+	picked  []int
+	toggles []int
+	started bool
+
+	// moved is set once the cursor has been sent to the first of the toggles, probe is the neighbour that it's moved
+	// to after toggling that option or -1 if it hasn't been toggled yet
+	moved bool
+	probe int
+}
+
+// newToggleCheck returns a toggleCheck that selects the options that pick returns for the answer.
+func newToggleCheck(answer string, pick func(answer string, options []option) ([]int, error)) *toggleCheck {
+	return &toggleCheck{answer: answer, pick: pick, probe: -1}
+}
+
+// next moves the cursor to the next option to toggle, toggles it and checks the result, see toggleCheck. If no
+// options are rendered, like when a form is answered line by line, all options are toggled at once without checking.
+func (c *toggleCheck) next(field []string) (string, bool, error) {
+	options := parseOptions(field)
+
+	if !c.started {
+		c.started = true
+
+		picked, err := c.pick(c.answer, options)
+		if err != nil {
+			return "", false, err
+		}
+
+		if len(options) == 0 {
+			if len(picked) == 0 {
+				return defaultSubmit, true, nil
+			}
+
+			return toggleOptions(make([]option, slices.Max(picked)+1), 0, picked) + defaultSubmit, true, nil
+		}
+
+		c.picked = picked
+		c.toggles = toggleOrder(options, picked)
+	}
+
+	if len(c.toggles) == 0 {
+		return defaultSubmit, true, nil
+	}
+
+	cursor := slices.IndexFunc(options, func(option option) bool { return option.cursor })
+	target := c.toggles[0]
+
+	if cursor == -1 || target >= len(options) {
+		return "", false, nil
+	}
+
+	if c.probe != -1 {
+		if cursor != c.probe {
+			return "", false, nil
+		}
+
+		if options[target].selected != slices.Contains(c.picked, target) {
+			var selected int
+
+			for _, option := range options {
+				if option.selected {
+					selected++
+				}
+			}
+
+			return "", false, fmt.Errorf("%q: %w of %d selected", options[target].label, errSelectionLimit, selected)
+		}
+
+		c.toggles = c.toggles[1:]
+		c.moved = false
+		c.probe = -1
+
+		return c.next(field)
+	}
+
+	if !c.moved {
+		c.moved = true
+
+		if cursor != target {
+			return navigate(cursor, target), false, nil
+		}
+	}
+
+	if cursor != target {
+		return "", false, nil
+	}
+
+	c.probe = target + 1
+	if c.probe == len(options) {
+		c.probe = target - 1
+	}
+
+	// The cursor can't move if there's only one option, but a limit can't get in the way of selecting it either
+	if c.probe < 0 {
+		c.toggles = nil
+
+		return selectOption + defaultSubmit, true, nil
+	}
+
+	return selectOption + navigate(target, c.probe), false, nil
+}
+
+// errAmbiguousFilter is returned if a filter leaves more than one option and none of them is labelled like the filter
+var errAmbiguousFilter = errors.New("filter matches more than one option")
+
+// filterPick selects options of a select or multi-select by typing a filter for every option, as the option has
+// to be picked from the options that remain once the filter has been applied. A filter has to leave exactly one
+// option, or an option with the filter as its label.
+type filterPick struct {
+	filters []string
+
+	// multi is set for a multi-select, where the filtered option is toggled instead of picked
+	multi bool
+
+	// filter is the index of the filter that's being applied, and typed is set once the keys to type it have been sent
+	filter int
+	typed  bool
+}
+
+// newFilterPick returns a filterPick that types the filters separated by labelSeparator in the answer.
+func newFilterPick(answer string, multi bool) *filterPick {
+	result := &filterPick{multi: multi}
+
+	if answer != "" {
+		result.filters = strings.Split(answer, labelSeparator)
+	}
+
+	return result
+}
+
+// next types the current filter and moves the cursor to the option it leaves once the field shows the filter. Huh
+// keeps filtering in a select until an option is picked, in a multi-select the filter is set using defaultSubmit
+// before the option is toggled. The filter of a multi-select is emptied before the next filter is typed.
+func (p *filterPick) next(field []string) (string, bool, error) {
+	if p.filter == len(p.filters) {
+		return defaultSubmit, true, nil
+	}
+
+	filter := p.filters[p.filter]
+
+	if !p.typed {
+		p.typed = true
+
+		return filterOptions + clearLine + typeText(filter), false, nil
+	}
+
+	if isInlineSelect(field) {
+		return "", false, fmt.Errorf("%q: %w", filter, errInlineSelect)
+	}
+
+	if len(field) == 0 || fieldTitle(field[0]) != filterOptions+filter {
+		return "", false, nil
+	}
+
+	options := parseOptions(field)
+
+	target, err := filteredOption(options, filter)
+	if err != nil {
+		return "", false, err
+	}
+
+	cursor := max(slices.IndexFunc(options, func(option option) bool { return option.cursor }), 0)
+	input := navigate(cursor, target) + defaultSubmit
+
+	if !p.multi {
+		return input, true, nil
+	}
+
+	if !options[target].selected {
+		input += selectOption
+	}
+
+	p.filter++
+
+	if p.filter == len(p.filters) {
+		return input + defaultSubmit, true, nil
+	}
+
+	return input + filterOptions + clearLine + typeText(p.filters[p.filter]), false, nil
+}
+
+// filteredOption returns the index of the option that the filter leaves, it returns errOptionNotFound if there are
+// none and errAmbiguousFilter listing the options if there are more and none of them is labelled like the filter.
+func filteredOption(options []option, filter string) (int, error) {
+	if len(options) == 1 {
+		return 0, nil
+	}
+
+	if index := slices.IndexFunc(options, func(option option) bool { return option.label == filter }); index != -1 {
+		return index, nil
+	}
+
+	if len(options) == 0 {
+		return 0, fmt.Errorf("%q: %w, the filter doesn't match any", filter, errOptionNotFound)
+	}
+
+	labels := make([]string, len(options))
+	for index, option := range options {
+		labels[index] = fmt.Sprintf("%q", option.label)
+	}
+
+	return 0, fmt.Errorf("%q %w: %s", filter, errAmbiguousFilter, strings.Join(labels, ", "))
+}
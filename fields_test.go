@@ -0,0 +1,741 @@
+package huhtest
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseOptions_ReturnsRenderedOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected []option
+	}{
+		"select": {
+			field: []string{"┃ Make a choice", "┃ Some description", "┃   a", "┃ > b", "┃   c"},
+			expected: []option{
+				{label: "a"},
+				{label: "b", cursor: true},
+				{label: "c"},
+			},
+		},
+		"blurred select": {
+			field: []string{"  Make a choice", "  > a", "    b"},
+			expected: []option{
+				{label: "a", cursor: true},
+				{label: "b"},
+			},
+		},
+		"multi select": {
+			field: []string{"┃ Pick", "┃ > ✓ a", "┃   • b", "┃   ✓ c"},
+			expected: []option{
+				{label: "a", cursor: true, selected: true},
+				{label: "b"},
+				{label: "c", selected: true},
+			},
+		},
+		"multi select with base theme": {
+			field: []string{"┃ Pick", "┃   [ ] a", "┃ > [•] b"},
+			expected: []option{
+				{label: "a"},
+				{label: "b", cursor: true, selected: true},
+			},
+		},
+		"no options": {
+			field: []string{"┃ How Are You Feeling?", "┃ >"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := parseOptions(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestResolveSelectLabel_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		label string
+		field []string
+
+		expected string
+	}{
+		"cursor on the option": {
+			label:    "a",
+			field:    []string{"┃ Title", "┃ > a", "┃   b"},
+			expected: "",
+		},
+		"option below the cursor": {
+			label:    "Terribly!",
+			field:    []string{"┃ Title", "┃ > Well!", "┃   It was OK!", "┃   Terribly!"},
+			expected: "<down><down>",
+		},
+		"option above the cursor": {
+			label:    "a",
+			field:    []string{"┃ Title", "┃   a", "┃   b", "┃ > c"},
+			expected: "<up><up>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := resolveSelectLabel(testData.label, fieldView{lines: testData.field})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestResolveSelectLabel_ReturnsErrorOnUnknownLabel(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := []string{"┃ Title", "┃ > a", "┃   b"}
+
+	// Act
+	result, err := resolveSelectLabel("c", fieldView{lines: field})
+
+	// Assert
+	require.ErrorIs(t, err, errOptionNotFound)
+	assert.EqualError(t, err, `"c": option not found, available options are "a", "b"`)
+	assert.Empty(t, result)
+}
+
+func TestResolveSelectLabel_ReturnsErrorOnInlineSelect(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := []string{"┃ Title← a →"}
+
+	// Act
+	result, err := resolveSelectLabel("b", fieldView{lines: field})
+
+	// Assert
+	require.ErrorIs(t, err, errInlineSelect)
+	assert.Empty(t, result)
+}
+
+func TestIsLoading_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected bool
+	}{
+		"loading":              {field: []string{"┃ Title", "┃ - Loading...", "┃"}, expected: true},
+		"other spinner frame":  {field: []string{"┃ Title", "┃ \\ Loading..."}, expected: true},
+		"select":               {field: []string{"┃ Title", "┃ > a", "┃   b"}, expected: false},
+		"option named loading": {field: []string{"┃ Title", "┃ > Loading..."}, expected: false},
+		"title named loading":  {field: []string{"┃ - Loading..."}, expected: false},
+		"nothing rendered":     {field: []string{}, expected: false},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := isLoading(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestIsInlineSelect_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected bool
+	}{
+		"inline select":               {field: []string{"┃ Title← a →"}, expected: true},
+		"inline select with an error": {field: []string{"┃ Title *← a →"}, expected: true},
+		"select":                      {field: []string{"┃ Title", "┃ > a", "┃   b"}, expected: false},
+		"option that ends with arrow": {field: []string{"┃ Title", "┃ > a →"}, expected: false},
+		"nothing rendered":            {field: []string{}, expected: false},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := isInlineSelect(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestIsMultiSelect_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected bool
+	}{
+		"multi-select":                {field: []string{"┃ Title", "┃ > • a", "┃   ✓ b"}, expected: true},
+		"multi-select with brackets":  {field: []string{"┃ Title", "┃ > [ ] a", "┃   [•] b"}, expected: true},
+		"select":                      {field: []string{"┃ Title", "┃ > a", "┃   b"}, expected: false},
+		"select with a bullet option": {field: []string{"┃ Title", "┃ > • a", "┃   b"}, expected: false},
+		"input":                       {field: []string{"┃ Title", "┃ > a"}, expected: false},
+		"nothing rendered":            {field: []string{}, expected: false},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := isMultiSelect(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestFocusedFieldType_ReturnsExpectedType(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field fieldView
+
+		expected fieldType
+	}{
+		"input":         {field: fieldView{lines: []string{"┃ Title", "┃ > a"}}, expected: fieldInput},
+		"text":          {field: fieldView{lines: []string{"┃ Title", "┃ a", "┃"}}, expected: fieldText},
+		"select":        {field: fieldView{lines: []string{"┃ Title", "┃ > a", "┃   b"}}, expected: fieldSelect},
+		"inline select": {field: fieldView{lines: []string{"┃ Title← a →"}}, expected: fieldSelect},
+		"multi-select":  {field: fieldView{lines: []string{"┃ Title", "┃ > • a", "┃   ✓ b"}}, expected: fieldMultiSelect},
+		"note":          {field: fieldView{lines: []string{"Title", "Description"}, note: true}, expected: fieldNote},
+		"confirm": {
+			field:    renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmAffirm, false),
+			expected: fieldConfirm,
+		},
+		"confirm without colours": {
+			field:    fieldView{lines: []string{"┃ Title?", "┃", "┃   Yes     No"}},
+			expected: fieldText,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := focusedFieldType(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestPickLabels_ReturnsIndexesOfLabels(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		labels []string
+		field  []string
+
+		expected []int
+	}{
+		"nothing": {
+			labels:   []string{},
+			field:    []string{"┃ Title", "┃ > • a", "┃   • b"},
+			expected: []int{},
+		},
+		"options in order": {
+			labels:   []string{"b", "c"},
+			field:    []string{"┃ Title", "┃ > • a", "┃   • b", "┃   • c"},
+			expected: []int{1, 2},
+		},
+		"options out of order": {
+			labels:   []string{"c", "a"},
+			field:    []string{"┃ Title", "┃ > • a", "┃   ✓ b", "┃   • c"},
+			expected: []int{2, 0},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			answer := strings.Join(testData.labels, labelSeparator)
+
+			// Act
+			result, err := pickLabels(answer, parseOptions(testData.field))
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestPickLabels_ReturnsErrorOnUnknownLabel(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := []string{"┃ Title", "┃ > • a", "┃   • b"}
+
+	// Act
+	result, err := pickLabels("a"+labelSeparator+"c", parseOptions(field))
+
+	// Assert
+	require.ErrorIs(t, err, errOptionNotFound)
+	assert.EqualError(t, err, `"c": option not found, available options are "a", "b"`)
+	assert.Empty(t, result)
+}
+
+func TestResolveSelectIndex_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		option int
+		field  []string
+
+		expected string
+	}{
+		"cursor on the option": {
+			option:   0,
+			field:    []string{"┃ Title", "┃ > a", "┃   b"},
+			expected: "<home>",
+		},
+		"option below the cursor": {
+			option:   2,
+			field:    []string{"┃ Title", "┃ > a", "┃   b", "┃   c"},
+			expected: "<home><down><down>",
+		},
+		"option above the cursor": {
+			option:   0,
+			field:    []string{"┃ Title", "┃   a", "┃   b", "┃ > c"},
+			expected: "<home>",
+		},
+		"options not rendered": {
+			option:   2,
+			field:    []string{"Title"},
+			expected: "<down><down>",
+		},
+		"inline select": {
+			option:   2,
+			field:    []string{"┃ Title← b →"},
+			expected: "<home><right><right>",
+		},
+		"option beyond the shown options": {
+			option:   3,
+			field:    []string{"┃ Title", "┃   b", "┃ > c"},
+			expected: "<home><down><down><down>",
+		},
+		"option above the shown options": {
+			option:   1,
+			field:    []string{"┃ Title", "┃   h", "┃ > i"},
+			expected: "<home><down>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := resolveSelectIndex(joinIndexes([]int{testData.option}), fieldView{lines: testData.field})
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestResolveSelectIndex_ReturnsErrorOnOptionOutOfRange(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := []string{"┃ Title", "┃ > a", "┃   b"}
+
+	// Act
+	result, err := resolveSelectIndex(joinIndexes([]int{-1}), fieldView{lines: field})
+
+	// Assert
+	require.ErrorIs(t, err, errOptionOutOfRange)
+	assert.EqualError(t, err, "-1: option out of range")
+	assert.Empty(t, result)
+}
+
+func TestToggleOptions_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		options []int
+		field   []string
+		cursor  int
+
+		expected string
+	}{
+		"nothing": {
+			options:  []int{},
+			field:    []string{"┃ Title", "┃ > • a", "┃   • b"},
+			expected: "",
+		},
+		"options in order": {
+			options:  []int{1, 2},
+			field:    []string{"┃ Title", "┃ > • a", "┃   • b", "┃   • c"},
+			expected: "<down> <down> ",
+		},
+		"options out of order": {
+			options:  []int{2, 0},
+			field:    []string{"┃ Title", "┃ > • a", "┃   • b", "┃   • c"},
+			expected: " <down><down> ",
+		},
+		"options around the cursor": {
+			options:  []int{0, 2},
+			field:    []string{"┃ Title", "┃   • a", "┃ > • b", "┃   • c"},
+			cursor:   1,
+			expected: "<up> <down><down> ",
+		},
+		"already selected options are not toggled": {
+			options:  []int{0, 1},
+			field:    []string{"┃ Title", "┃ > ✓ a", "┃   • b"},
+			expected: "<down> ",
+		},
+		"other selected options are deselected": {
+			options:  []int{0},
+			field:    []string{"┃ Title", "┃ > • a", "┃   ✓ b", "┃   ✓ c"},
+			expected: "<down> <down> <up><up> ",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := toggleOptions(parseOptions(testData.field), testData.cursor, testData.options)
+
+			// Assert
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestPickIndexes_ReturnsErrorOnOptionOutOfRange(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	field := []string{"┃ Title", "┃ > • a", "┃   • b"}
+
+	// Act
+	result, err := pickIndexes(joinIndexes([]int{0, -1}), parseOptions(field))
+
+	// Assert
+	require.ErrorIs(t, err, errOptionOutOfRange)
+	assert.EqualError(t, err, "-1: option out of range, 2 options are shown")
+	assert.Empty(t, result)
+}
+
+// fakeMultiSelect handles the keys that a toggleCheck sends like a huh.MultiSelect would, including its limit.
+type fakeMultiSelect struct {
+	labels   []string
+	selected []bool
+	cursor   int
+	limit    int
+}
+
+// lines renders the multi-select like huh's base theme does.
+func (f *fakeMultiSelect) lines() []string {
+	result := []string{"┃ Title"}
+
+	for index, label := range f.labels {
+		selector, prefix := "  ", "• "
+
+		if index == f.cursor {
+			selector = "> "
+		}
+
+		if f.selected[index] {
+			prefix = "✓ "
+		}
+
+		result = append(result, "┃ "+selector+prefix+label)
+	}
+
+	return result
+}
+
+// press handles the arrow keys and toggles in the input, anything else is ignored.
+func (f *fakeMultiSelect) press(input string) {
+	for input != "" {
+		switch {
+		case strings.HasPrefix(input, arrowDown):
+			f.cursor = min(f.cursor+1, len(f.labels)-1)
+			input = strings.TrimPrefix(input, arrowDown)
+
+		case strings.HasPrefix(input, arrowUp):
+			f.cursor = max(f.cursor-1, 0)
+			input = strings.TrimPrefix(input, arrowUp)
+
+		case strings.HasPrefix(input, selectOption):
+			if f.selected[f.cursor] || f.limit == 0 || len(slices.DeleteFunc(slices.Clone(f.selected), func(s bool) bool { return !s })) < f.limit {
+				f.selected[f.cursor] = !f.selected[f.cursor]
+			}
+
+			input = strings.TrimPrefix(input, selectOption)
+
+		default:
+			input = input[1:]
+		}
+	}
+}
+
+// runToggleCheck answers the multi-select using the toggleCheck and returns everything that it sent.
+func runToggleCheck(t *testing.T, check *toggleCheck, form *fakeMultiSelect) (string, error) {
+	t.Helper()
+
+	var sent strings.Builder
+
+	for range 20 {
+		input, last, err := check.next(form.lines())
+		if err != nil {
+			return readableReplacer.Replace(sent.String()), err
+		}
+
+		sent.WriteString(input)
+		form.press(input)
+
+		if last {
+			return readableReplacer.Replace(sent.String()), nil
+		}
+	}
+
+	t.Fatal("toggleCheck didn't finish")
+
+	return "", nil
+}
+
+func TestToggleCheck_Next_SelectsPickedOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		answer   string
+		pick     func(answer string, options []option) ([]int, error)
+		selected []bool
+		cursor   int
+		limit    int
+
+		expectedInput    string
+		expectedSelected []bool
+	}{
+		"indexes": {
+			answer:           joinIndexes([]int{1, 2}),
+			pick:             pickIndexes,
+			selected:         []bool{false, false, false},
+			expectedInput:    "<down> <down> <up><submit>",
+			expectedSelected: []bool{false, true, true},
+		},
+		"labels with selected options": {
+			answer:           "a",
+			pick:             pickLabels,
+			selected:         []bool{false, true, false},
+			cursor:           2,
+			expectedInput:    "<up> <down><up><up> <down><submit>",
+			expectedSelected: []bool{true, false, false},
+		},
+		"all within the limit": {
+			pick:             pickAll,
+			selected:         []bool{false, true, false},
+			limit:            3,
+			expectedInput:    " <down><down> <up><submit>",
+			expectedSelected: []bool{true, true, true},
+		},
+		"none": {
+			pick:             pickNone,
+			selected:         []bool{true, false, true},
+			expectedInput:    " <down><down> <up><submit>",
+			expectedSelected: []bool{false, false, false},
+		},
+		"nothing to toggle": {
+			pick:             pickNone,
+			selected:         []bool{false, false, false},
+			expectedInput:    "<submit>",
+			expectedSelected: []bool{false, false, false},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			form := &fakeMultiSelect{
+				labels:   []string{"a", "b", "c"},
+				selected: testData.selected,
+				cursor:   testData.cursor,
+				limit:    testData.limit,
+			}
+
+			// Act
+			result, err := runToggleCheck(t, newToggleCheck(testData.answer, testData.pick), form)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expectedInput, result)
+			assert.Equal(t, testData.expectedSelected, form.selected)
+		})
+	}
+}
+
+func TestToggleCheck_Next_SelectsOnlyOption(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := &fakeMultiSelect{labels: []string{"a"}, selected: []bool{false}}
+
+	// Act
+	result, err := runToggleCheck(t, newToggleCheck("", pickAll), form)
+
+	// Assert
+	require.NoError(t, err)
+	assert.Equal(t, " <submit>", result)
+	assert.Equal(t, []bool{true}, form.selected)
+}
+
+func TestToggleCheck_Next_TogglesAtOnceWithoutRenderedOptions(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	check := newToggleCheck(joinIndexes([]int{1, 3}), pickIndexes)
+
+	// Act
+	result, last, err := check.next([]string{"Title"})
+
+	// Assert
+	require.NoError(t, err)
+	assert.True(t, last)
+	assert.Equal(t, "<down> <down><down> <submit>", readableReplacer.Replace(result))
+}
+
+func TestToggleCheck_Next_ReturnsErrorOnSelectionLimit(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := &fakeMultiSelect{labels: []string{"a", "b", "c"}, selected: []bool{false, false, false}, limit: 2}
+
+	// Act
+	result, err := runToggleCheck(t, newToggleCheck("", pickAll), form)
+
+	// Assert
+	require.ErrorIs(t, err, errSelectionLimit)
+	assert.EqualError(t, err, `"c": option can't be selected, the field has reached its limit of 2 selected`)
+	assert.Equal(t, " <down> <down> <up>", result)
+}
+
+func TestFilterPick_Next_ReturnsExpectedSteps(t *testing.T) {
+	t.Parallel()
+
+	type step struct {
+		field []string
+
+		expectedInput string
+		expectedLast  bool
+	}
+
+	tests := map[string]struct {
+		answer string
+		multi  bool
+		steps  []step
+	}{
+		"select": {
+			answer: "eu",
+			steps: []step{
+				{field: []string{"┃ Region?", "┃ > us", "┃   eu"}, expectedInput: "/<clear>eu"},
+				{field: []string{"┃ /e", "┃ > us", "┃   eu"}},
+				{field: []string{"┃ /eu", "┃ > eu"}, expectedInput: "<submit>", expectedLast: true},
+			},
+		},
+		"select with label of filter": {
+			answer: "eu",
+			steps: []step{
+				{field: []string{"┃ Region?", "┃ > us", "┃   eu", "┃   eu-west"}, expectedInput: "/<clear>eu"},
+				{field: []string{"┃ /eu", "┃ > eu-west", "┃   eu"}, expectedInput: "<down><submit>", expectedLast: true},
+			},
+		},
+		"multi select": {
+			answer: "eu" + labelSeparator + "us",
+			multi:  true,
+			steps: []step{
+				{field: []string{"┃ Regions?", "┃ > • us", "┃   • eu"}, expectedInput: "/<clear>eu"},
+				{field: []string{"┃ /eu", "┃ > • eu"}, expectedInput: "<submit> /<clear>us"},
+				{field: []string{"┃ /us", "┃ > ✓ us"}, expectedInput: "<submit><submit>", expectedLast: true},
+			},
+		},
+		"multi select without filters": {
+			multi: true,
+			steps: []step{
+				{field: []string{"┃ Regions?", "┃ > • us"}, expectedInput: "<submit>", expectedLast: true},
+			},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			pick := newFilterPick(testData.answer, testData.multi)
+
+			for _, step := range testData.steps {
+				// Act
+				input, last, err := pick.next(step.field)
+
+				// Assert
+				require.NoError(t, err)
+				assert.Equal(t, step.expectedInput, readableReplacer.Replace(input))
+				assert.Equal(t, step.expectedLast, last)
+			}
+		})
+	}
+}
+
+func TestFilteredOption_ReturnsErrorOnUnexpectedOptions(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		options []option
+
+		expectedErr   error
+		expectedError string
+	}{
+		"no options": {
+			expectedErr:   errOptionNotFound,
+			expectedError: `"b": option not found, the filter doesn't match any`,
+		},
+		"multiple options": {
+			options:       []option{{label: "ab"}, {label: "bc"}},
+			expectedErr:   errAmbiguousFilter,
+			expectedError: `"b" filter matches more than one option: "ab", "bc"`,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := filteredOption(testData.options, "b")
+
+			// Assert
+			require.ErrorIs(t, err, testData.expectedErr)
+			assert.EqualError(t, err, testData.expectedError)
+			assert.Zero(t, result)
+		})
+	}
+}
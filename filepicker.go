@@ -0,0 +1,243 @@
+package huhtest
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+var (
+	// errFileNotFound is returned if a file isn't shown in the directory listing of a file picker
+	errFileNotFound = errors.New("file not found")
+
+	// errNotADirectory is returned if a path goes through a file as if it's a directory
+	errNotADirectory = errors.New("not a directory")
+
+	// errUnexpectedDirectory is returned if a file picker shows files that aren't in any of the directories along the
+	// path, which happens if its current directory isn't the directory of the file system, see Responder.WithFileSystem
+	errUnexpectedDirectory = errors.New("file picker shows a directory that isn't along the path")
+)
+
+// listDirectory returns the entries of a directory in the order a huh.FilePicker shows them, which is directories
+// first and then by name. Hidden entries are left out, as the file picker doesn't show them by default.
+func listDirectory(fsys fs.FS, directory string) ([]fs.DirEntry, error) {
+	entries, err := fs.ReadDir(fsys, directory)
+	if err != nil {
+		return nil, err
+	}
+
+	entries = slices.DeleteFunc(entries, func(entry fs.DirEntry) bool { return strings.HasPrefix(entry.Name(), ".") })
+
+	slices.SortStableFunc(entries, func(a, b fs.DirEntry) int {
+		switch {
+		case a.IsDir() == b.IsDir():
+			return strings.Compare(a.Name(), b.Name())
+		case a.IsDir():
+			return -1
+		default:
+			return 1
+		}
+	})
+
+	return entries, nil
+}
+
+// filePick navigates a file picker to a path one directory at a time, as the file picker only shows the
+// contents of a directory after it has been opened.
+type filePick struct {
+	// listings contains the entries of every directory along the path and targets contains the index of the
+	// path's element in each of them
+	listings [][]fs.DirEntry
+	targets  []int
+
+	// directory is the index in listings of the directory that the picker is in, or len(listings) once the file
+	// has been picked
+	directory int
+
+	// start is the index in listings of the directory that the picker started in, or -1 until it's shown
+	start int
+
+	// back is set once the keys to go back to the directory are sent, until the picker shows it
+	back bool
+
+	// opened is set once the keys to open the file picker have been sent
+	opened bool
+}
+
+// newFilePick checks that every element of the relative path is shown in the file picker and returns a filePick
+// that navigates to it. It returns errFileNotFound listing the available files if an element is missing.
+func newFilePick(fsys fs.FS, name string) (*filePick, error) {
+	name = path.Clean(filepath.ToSlash(name))
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "pick", Path: name, Err: fs.ErrInvalid}
+	}
+
+	elements := strings.Split(name, "/")
+	result := &filePick{start: -1}
+	directory := "."
+
+	for index, element := range elements {
+		entries, err := listDirectory(fsys, directory)
+		if err != nil {
+			return nil, err
+		}
+
+		target := slices.IndexFunc(entries, func(entry fs.DirEntry) bool { return entry.Name() == element })
+		if target == -1 {
+			available := make([]string, len(entries))
+			for entryIndex, entry := range entries {
+				available[entryIndex] = fmt.Sprintf("%q", entry.Name())
+			}
+
+			return nil, fmt.Errorf("%q: %w, available files in %q are %s", element, errFileNotFound, directory, strings.Join(available, ", "))
+		}
+
+		if index < len(elements)-1 && !entries[target].IsDir() {
+			return nil, fmt.Errorf("%q: %w", path.Join(directory, element), errNotADirectory)
+		}
+
+		result.listings = append(result.listings, entries)
+		result.targets = append(result.targets, target)
+
+		directory = path.Join(directory, element)
+	}
+
+	return result, nil
+}
+
+// next opens the file picker if it's closed and navigates to the next element of the path once the picker shows
+// the directory that contains it. Directories are opened using arrowRight and the last element is picked using
+// defaultSubmit. If the picker starts in a directory along the path, it's navigated from there. It returns
+// errUnexpectedDirectory if the picker shows a directory that isn't along the path.
+//
+// huh reads the directory that the picker starts in more than once, and a read that arrives late replaces the files
+// that the picker shows after a directory was opened. If the picker shows an earlier directory than the one it's in,
+// it's either still reading the directory or such a read arrived, which is fixed by closing the picker using
+// escapeKey and opening it again to read its directory. If the late read arrived before the keys to open a directory
+// or pick the file were handled, the picker opened a directory that doesn't exist, which is fixed by going back
+// using arrowLeft. As this can happen after the file was picked, it's never the last step and the picker is
+// followed until another field gets focus.
+func (p *filePick) next(field []string) (string, bool, error) {
+	options := parseOptions(field)
+
+	cursor := slices.IndexFunc(options, func(option option) bool { return option.cursor })
+	if cursor == -1 {
+		if p.opened {
+			return "", false, nil
+		}
+
+		p.opened = true
+
+		return arrowRight, false, nil
+	}
+
+	p.opened = true
+
+	shown, current := p.shownDirectory(options, cursor)
+	if shown == -1 {
+		labels := make([]string, len(options))
+		for index, option := range options {
+			labels[index] = fmt.Sprintf("%q", option.label)
+		}
+
+		return "", false, fmt.Errorf("%w, it shows %s", errUnexpectedDirectory, strings.Join(labels, ", "))
+	}
+
+	if p.start == -1 {
+		p.start, p.directory = shown, shown
+	}
+
+	switch {
+	// The directory that the picker went back from was read after the one it's in
+	case shown > p.directory:
+		return escapeKey + arrowRight, false, nil
+
+	// The file was picked, but the picker hasn't handled it yet
+	case p.directory == len(p.listings) && shown == p.directory-1:
+		return "", false, nil
+
+	// The directory that was opened or gone back to hasn't been read yet
+	case shown < p.directory && (p.back || shown != p.start):
+		return "", false, nil
+
+	// Only a directory that was opened afterwards can be missing, reading this one again is safe either way
+	case shown < p.directory && p.directory == p.start+1 && p.directory < len(p.listings):
+		return escapeKey + arrowRight, false, nil
+
+	case shown < p.directory:
+		p.directory--
+		p.back = true
+
+		return arrowLeft, false, nil
+	}
+
+	p.back = false
+
+	input := navigate(current, p.targets[p.directory])
+
+	p.directory++
+
+	if p.directory == len(p.listings) {
+		return input + defaultSubmit, false, nil
+	}
+
+	return input + arrowRight, false, nil
+}
+
+// shownDirectory returns the index in listings of the directory that the options are shown from, along with the
+// index of the entry under the cursor in it, or -1 if none of the directories contain every option. The directory
+// that's being navigated is preferred, unless its cursor isn't at the top where an opened directory starts, then
+// the one before it is preferred as the picker is still showing it.
+func (p *filePick) shownDirectory(options []option, cursor int) (int, int) {
+	shows := func(directory int) (int, bool) {
+		if directory < 0 || directory >= len(p.listings) {
+			return -1, false
+		}
+
+		for _, option := range options {
+			if findEntry(p.listings[directory], option.label) == -1 {
+				return -1, false
+			}
+		}
+
+		return findEntry(p.listings[directory], options[cursor].label), true
+	}
+
+	if current, ok := shows(p.directory); ok && (p.directory == 0 || current == 0) {
+		return p.directory, current
+	}
+
+	if current, ok := shows(p.directory - 1); ok {
+		return p.directory - 1, current
+	}
+
+	for directory := range p.listings {
+		if current, ok := shows(directory); ok {
+			return directory, current
+		}
+	}
+
+	return -1, -1
+}
+
+// findEntry returns the index of the entry that's shown in the label, or -1 if none of them are. The label may
+// start with details like permissions, so the longest name at the end of the label is picked.
+func findEntry(entries []fs.DirEntry, label string) int {
+	result := -1
+
+	for index, entry := range entries {
+		if label != entry.Name() && !strings.HasSuffix(label, " "+entry.Name()) {
+			continue
+		}
+
+		if result == -1 || len(entry.Name()) > len(entries[result].Name()) {
+			result = index
+		}
+	}
+
+	return result
+}
@@ -0,0 +1,236 @@
+package huhtest
+
+import (
+	"io/fs"
+	"testing"
+	"testing/fstest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testFileSystem = fstest.MapFS{
+	"b.txt":             {},
+	"a.txt":             {},
+	".hidden":           {},
+	"sub/c.txt":         {},
+	"sub/deeper/d.txt":  {},
+	"another/e.txt":     {},
+	"another/.f.txt":    {},
+	"another/deep/g.md": {},
+}
+
+func TestListDirectory_ReturnsEntriesInOrder(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		directory string
+
+		expected []string
+	}{
+		"root": {
+			directory: ".",
+			expected:  []string{"another", "sub", "a.txt", "b.txt"},
+		},
+		"sub directory": {
+			directory: "another",
+			expected:  []string{"deep", "e.txt"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := listDirectory(testFileSystem, testData.directory)
+
+			// Assert
+			require.NoError(t, err)
+
+			names := make([]string, len(result))
+			for index, entry := range result {
+				names[index] = entry.Name()
+			}
+
+			assert.Equal(t, testData.expected, names)
+		})
+	}
+}
+
+func TestNewFilePick_ReturnsErrorOnUnknownPath(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		path string
+
+		expectedErr   error
+		expectedError string
+	}{
+		"missing file": {
+			path:          "sub/e.txt",
+			expectedErr:   errFileNotFound,
+			expectedError: `"e.txt": file not found, available files in "sub" are "deeper", "c.txt"`,
+		},
+		"hidden file": {
+			path:          ".hidden",
+			expectedErr:   errFileNotFound,
+			expectedError: `".hidden": file not found, available files in "." are "another", "sub", "a.txt", "b.txt"`,
+		},
+		"file as directory": {
+			path:          "a.txt/b.txt",
+			expectedErr:   errNotADirectory,
+			expectedError: `"a.txt": not a directory`,
+		},
+		"outside of file system": {
+			path:          "../a.txt",
+			expectedErr:   fs.ErrInvalid,
+			expectedError: "pick ../a.txt: invalid argument",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := newFilePick(testFileSystem, testData.path)
+
+			// Assert
+			require.ErrorIs(t, err, testData.expectedErr)
+			assert.EqualError(t, err, testData.expectedError)
+			assert.Nil(t, result)
+		})
+	}
+}
+
+func TestFilePick_Next_ReturnsExpectedSteps(t *testing.T) {
+	t.Parallel()
+
+	type step struct {
+		field []string
+
+		expectedInput string
+	}
+
+	tests := map[string]struct {
+		path  string
+		steps []step
+	}{
+		"file in root": {
+			path: "b.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ No file selected."}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ No file selected."}, expectedInput: ""},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: "<down><down><down><submit>"},
+			},
+		},
+		"file in sub directories": {
+			path: "sub/deeper/d.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ No file selected."}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: "<down><right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x sub"}, expectedInput: "<esc><right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x deeper"}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<submit>"},
+			},
+		},
+		"picker that read the directory it started in late": {
+			path: "sub/deeper/d.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: "<down><right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x deeper"}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x sub"}, expectedInput: "<left>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: ""},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<esc><right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x deeper"}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<submit>"},
+			},
+		},
+		"picker that read the directory it started in late after the file was picked": {
+			path: "sub/deeper/d.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: "<down><right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x deeper"}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<submit>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: ""},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x another"}, expectedInput: "<left>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<submit>"},
+			},
+		},
+		"picker that's already open": {
+			path: "a.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ > b.txt"}, expectedInput: "<up><submit>"},
+			},
+		},
+		"picker that starts in a sub directory": {
+			path: "sub/deeper/d.txt",
+			steps: []step{
+				{field: []string{"┃ Pick", "┃ No file selected."}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > drwxr-xr-x deeper", "┃   -rw-r--r-- c.txt"}, expectedInput: "<right>"},
+				{field: []string{"┃ Pick", "┃ > -rw-r--r-- d.txt"}, expectedInput: "<submit>"},
+			},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			pick, err := newFilePick(testFileSystem, testData.path)
+			require.NoError(t, err)
+
+			for _, step := range testData.steps {
+				// Act
+				input, last, err := pick.next(step.field)
+
+				// Assert
+				require.NoError(t, err)
+
+				assert.Equal(t, step.expectedInput, readableReplacer.Replace(input))
+				assert.False(t, last)
+			}
+		})
+	}
+}
+
+func TestFilePick_Next_ReturnsErrorOnUnexpectedDirectory(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	pick, err := newFilePick(testFileSystem, "sub/c.txt")
+	require.NoError(t, err)
+
+	// Act
+	input, last, err := pick.next([]string{"┃ Pick", "┃ > drwxr-xr-x testdata", "┃   -rw-r--r-- go.mod"})
+
+	// Assert
+	require.ErrorIs(t, err, errUnexpectedDirectory)
+	assert.EqualError(t, err, `file picker shows a directory that isn't along the path, it shows "drwxr-xr-x testdata", "-rw-r--r-- go.mod"`)
+
+	assert.Empty(t, input)
+	assert.False(t, last)
+}
+
+func TestFindEntry_ReturnsEntryInLabel(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	entries, err := listDirectory(fstest.MapFS{"b.txt": {}, "a b.txt": {}}, ".")
+	require.NoError(t, err)
+
+	tests := map[string]int{
+		"-rw-r--r-- b.txt":   1,
+		"-rw-r--r-- a b.txt": 0,
+		"a b.txt":            0,
+		"c.txt":              -1,
+	}
+
+	for label, expected := range tests {
+		t.Run(label, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := findEntry(entries, label)
+
+			// Assert
+			assert.Equal(t, expected, result)
+		})
+	}
+}
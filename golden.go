@@ -0,0 +1,220 @@
+package huhtest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	testingi "github.com/mitchellh/go-testing-interface"
+)
+
+// updateFlag is the flag that makes AssertScreen and AssertSnapshots write their golden files instead of comparing
+// against them, like go test ./... -update. We don't define it ourselves, as test packages often define an -update
+// flag of their own and defining it twice panics. It's only used if the test binary defines it.
+const updateFlag = "update"
+
+// updateVariable is the environment variable that does the same as updateFlag, for test binaries that don't define
+// the flag, like HUHTEST_UPDATE=1 go test ./...
+const updateVariable = "HUHTEST_UPDATE"
+
+// goldenExtension is the extension of the golden files written by AssertSnapshots
+const goldenExtension = ".golden"
+
+// nonAlphanumeric matches the characters of a question that are left out of the name of its golden file
+var nonAlphanumeric = regexp.MustCompile(`[^a-z0-9]+`)
+
+// snapshotFile matches the names of the golden files written by AssertSnapshots, see snapshotName. Other golden files
+// in the same directory are left alone.
+var snapshotFile = regexp.MustCompile(`^[0-9]{2,}(-[a-z0-9-]+)?\.golden$`)
+
+// updating returns true if the golden files should be written, which is the case if the -update flag of the test
+// binary or the HUHTEST_UPDATE environment variable is true. See updateFlag and updateVariable.
+func updating() bool {
+	if defined := flag.Lookup(updateFlag); defined != nil {
+		if update, _ := strconv.ParseBool(defined.Value.String()); update {
+			return true
+		}
+	}
+
+	update, _ := strconv.ParseBool(os.Getenv(updateVariable))
+
+	return update
+}
+
+// screenOptions contains the settings of AssertScreen and AssertSnapshots that can be changed using ScreenOption.
+type screenOptions struct {
+	styled bool
+}
+
+// ScreenOption changes the way AssertScreen and AssertSnapshots look at the screen.
+type ScreenOption func(*screenOptions)
+
+// WithStyles makes AssertScreen and AssertSnapshots keep the colours and other styles of the screen, as select
+// graphic rendition sequences. Styles are only rendered if lipgloss uses a colour profile, see the README.
+func WithStyles() ScreenOption {
+	return func(options *screenOptions) {
+		options.styled = true
+	}
+}
+
+// snapshot is what the screen looked like when a question was answered, see AssertSnapshots.
+type snapshot struct {
+	question string
+	plain    string
+	styled   string
+}
+
+// text returns the screen of the snapshot, with or without styles.
+func (s snapshot) text(options screenOptions) string {
+	if options.styled {
+		return s.styled
+	}
+
+	return s.plain
+}
+
+// snapshot records the current screen for the question that's about to be answered
+func (r *Responder) snapshot(question string) {
+	r.screenLock.Lock()
+	defer r.screenLock.Unlock()
+
+	r.snapshots = append(r.snapshots, snapshot{question: question, plain: r.screen.String(), styled: r.screen.styledString()})
+}
+
+// AssertScreen fails the test if what the form currently shows doesn't match the golden file, which is written
+// instead if the tests are run with -update or HUHTEST_UPDATE=1, see updating. The screen is compared without styles,
+// unless WithStyles is given. Returns whether the assertion succeeded.
+//
+// Usage:
+//
+//	huhtest.AssertScreen(t, responder, "testdata/step1.golden")
+func AssertScreen(t testingi.T, responder *Responder, goldenFile string, opts ...ScreenOption) bool {
+	t.Helper()
+
+	var options screenOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	responder.screenLock.Lock()
+	current := snapshot{plain: responder.screen.String(), styled: responder.screen.styledString()}
+	responder.screenLock.Unlock()
+
+	return assertGolden(t, goldenFile, current.text(options), updating())
+}
+
+// AssertSnapshots fails the test if the screen at any of the questions that the responder answered doesn't match
+// its golden file in the directory. Every question gets its own file, numbered in the order they were answered, like
+// 01-what-s-your-name.golden. If the tests are run with -update or HUHTEST_UPDATE=1, the golden files are written
+// instead and the ones of questions that weren't answered are removed. Other files in the directory are left alone,
+// so it can hold the golden files of AssertScreen as well. Returns whether the assertion succeeded.
+//
+// Call it after the form is done, the screens are recorded just before the answers are sent. See AssertScreen.
+func AssertSnapshots(t testingi.T, responder *Responder, goldenDir string, opts ...ScreenOption) bool {
+	t.Helper()
+
+	var options screenOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	responder.screenLock.Lock()
+	snapshots := slices.Clone(responder.snapshots)
+	responder.screenLock.Unlock()
+
+	return assertSnapshots(t, snapshots, goldenDir, options, updating())
+}
+
+// assertSnapshots compares the snapshots to the golden files in the directory, or writes them, see AssertSnapshots
+func assertSnapshots(t testingi.T, snapshots []snapshot, goldenDir string, options screenOptions, write bool) bool {
+	t.Helper()
+
+	ok := true
+	names := make([]string, len(snapshots))
+
+	for index, snapshot := range snapshots {
+		names[index] = snapshotName(index, snapshot.question)
+		ok = assertGolden(t, filepath.Join(goldenDir, names[index]), snapshot.text(options), write) && ok
+	}
+
+	existing, err := filepath.Glob(filepath.Join(goldenDir, "*"+goldenExtension))
+	if err != nil {
+		t.Errorf("Failed to list golden files: %s", err)
+		return false
+	}
+
+	for _, goldenFile := range existing {
+		if name := filepath.Base(goldenFile); !snapshotFile.MatchString(name) || slices.Contains(names, name) {
+			continue
+		}
+
+		if !write {
+			t.Errorf("Golden file %s doesn't belong to any of the answered questions, run the tests with -%s or %s=1 to remove it", goldenFile, updateFlag, updateVariable)
+			ok = false
+
+			continue
+		}
+
+		if err := os.Remove(goldenFile); err != nil {
+			t.Errorf("Failed to remove golden file: %s", err)
+			ok = false
+		}
+	}
+
+	return ok
+}
+
+// snapshotName returns the name of the golden file of a snapshot, made up of its position and question.
+func snapshotName(index int, question string) string {
+	slug := strings.Trim(nonAlphanumeric.ReplaceAllString(strings.ToLower(question), "-"), "-")
+	if slug == "" {
+		return fmt.Sprintf("%02d%s", index+1, goldenExtension)
+	}
+
+	return fmt.Sprintf("%02d-%s%s", index+1, slug, goldenExtension)
+}
+
+// assertGolden fails the test if the screen doesn't match the golden file, or writes the golden file if write is true.
+// Golden files end with a newline, so they're easier to edit.
+func assertGolden(t testingi.T, goldenFile string, screen string, write bool) bool {
+	t.Helper()
+
+	if write {
+		if err := os.MkdirAll(filepath.Dir(goldenFile), 0o755); err != nil {
+			t.Errorf("Failed to create directory of golden file: %s", err)
+			return false
+		}
+
+		if err := os.WriteFile(goldenFile, []byte(screen+"\n"), 0o644); err != nil {
+			t.Errorf("Failed to write golden file: %s", err)
+			return false
+		}
+
+		return true
+	}
+
+	expected, err := os.ReadFile(goldenFile)
+	if errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Golden file %s doesn't exist, run the tests with -%s or %s=1 to create it", goldenFile, updateFlag, updateVariable)
+		return false
+	}
+
+	if err != nil {
+		t.Errorf("Failed to read golden file: %s", err)
+		return false
+	}
+
+	if strings.TrimSuffix(string(expected), "\n") != screen {
+		t.Errorf("Screen doesn't match golden file %s, run the tests with -%s or %s=1 to update it.\nExpected:\n%s\n\nActual:\n%s", goldenFile, updateFlag, updateVariable, expected, screen)
+		return false
+	}
+
+	return true
+}
@@ -0,0 +1,318 @@
+package huhtest
+
+import (
+	"flag"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	testingi "github.com/mitchellh/go-testing-interface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// update lets the golden files in testdata be written using go test -update, see updating
+var update = flag.Bool(updateFlag, false, "write the golden files instead of comparing against them")
+
+func TestSnapshotName_ReturnsNameOfGoldenFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		index    int
+		question string
+
+		expected string
+	}{
+		"simple": {
+			index:    0,
+			question: "Name",
+			expected: "01-name.golden",
+		},
+		"punctuation": {
+			index:    11,
+			question: "What's your name?",
+			expected: "12-what-s-your-name.golden",
+		},
+		"no letters": {
+			index:    2,
+			question: "🤔?",
+			expected: "03.golden",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := snapshotName(testData.index, testData.question)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestUpdating_ReturnsWhetherFlagOrVariableIsSet(t *testing.T) {
+	tests := map[string]struct {
+		flag     bool
+		variable string
+
+		expected bool
+	}{
+		"nothing": {
+			variable: "",
+			expected: false,
+		},
+		"flag": {
+			flag:     true,
+			variable: "",
+			expected: true,
+		},
+		"variable": {
+			variable: "1",
+			expected: true,
+		},
+		"flag and variable": {
+			flag:     true,
+			variable: "1",
+			expected: true,
+		},
+		"false variable": {
+			variable: "0",
+			expected: false,
+		},
+		"invalid variable": {
+			variable: "yes please",
+			expected: false,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			// Arrange
+			t.Setenv(updateVariable, testData.variable)
+
+			previous := *update
+			*update = testData.flag
+
+			t.Cleanup(func() { *update = previous })
+
+			// Act
+			result := updating()
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestAssertGolden_WritesGoldenFile(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	goldenFile := filepath.Join(t.TempDir(), "nested", "screen.golden")
+
+	// Act
+	result := assertGolden(t, goldenFile, "┃ a?\n┃ > b", true)
+
+	// Assert
+	assert.True(t, result)
+
+	contents, err := os.ReadFile(goldenFile)
+	require.NoError(t, err)
+	assert.Equal(t, "┃ a?\n┃ > b\n", string(contents))
+}
+
+func TestAssertGolden_ComparesScreenWithGoldenFile(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		contents string
+		missing  bool
+		screen   string
+
+		expected bool
+	}{
+		"equal": {
+			contents: "┃ a?\n",
+			screen:   "┃ a?",
+			expected: true,
+		},
+		"equal without trailing newline": {
+			contents: "┃ a?",
+			screen:   "┃ a?",
+			expected: true,
+		},
+		"different": {
+			contents: "┃ a?\n",
+			screen:   "┃ b?",
+			expected: false,
+		},
+		"missing": {
+			missing:  true,
+			screen:   "┃ a?",
+			expected: false,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			goldenFile := filepath.Join(t.TempDir(), "screen.golden")
+			if !testData.missing {
+				require.NoError(t, os.WriteFile(goldenFile, []byte(testData.contents), 0o644))
+			}
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			result := assertGolden(dummyT, goldenFile, testData.screen, false)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+			assert.Equal(t, !testData.expected, dummyT.Failed())
+		})
+	}
+}
+
+func TestAssertSnapshots_ComparesSnapshotsWithGoldenFiles(t *testing.T) {
+	t.Parallel()
+
+	snapshots := []snapshot{
+		{question: "a?", plain: "┃ a?", styled: "\x1b[1m┃ a?\x1b[m"},
+		{question: "b?", plain: "┃ b?", styled: "\x1b[1m┃ b?\x1b[m"},
+	}
+
+	tests := map[string]struct {
+		files   map[string]string
+		options screenOptions
+
+		expected bool
+	}{
+		"equal": {
+			files:    map[string]string{"01-a.golden": "┃ a?\n", "02-b.golden": "┃ b?\n"},
+			expected: true,
+		},
+		"equal with styles": {
+			files:    map[string]string{"01-a.golden": "\x1b[1m┃ a?\x1b[m\n", "02-b.golden": "\x1b[1m┃ b?\x1b[m\n"},
+			options:  screenOptions{styled: true},
+			expected: true,
+		},
+		"different": {
+			files:    map[string]string{"01-a.golden": "┃ a?\n", "02-b.golden": "┃ c?\n"},
+			expected: false,
+		},
+		"missing": {
+			files:    map[string]string{"01-a.golden": "┃ a?\n"},
+			expected: false,
+		},
+		"unexpected": {
+			files:    map[string]string{"01-a.golden": "┃ a?\n", "02-b.golden": "┃ b?\n", "03-c.golden": "┃ c?\n"},
+			expected: false,
+		},
+		"other golden file": {
+			files:    map[string]string{"01-a.golden": "┃ a?\n", "02-b.golden": "┃ b?\n", "screen.golden": "┃ c?\n"},
+			expected: true,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			goldenDir := t.TempDir()
+			for file, contents := range testData.files {
+				require.NoError(t, os.WriteFile(filepath.Join(goldenDir, file), []byte(contents), 0o644))
+			}
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			result := assertSnapshots(dummyT, snapshots, goldenDir, testData.options, false)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+			assert.Equal(t, !testData.expected, dummyT.Failed())
+		})
+	}
+}
+
+func TestAssertSnapshots_WritesGoldenFilesAndRemovesOthers(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	goldenDir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(goldenDir, "01-c.golden"), []byte("┃ c?\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(goldenDir, "notes.txt"), []byte("notes\n"), 0o644))
+	require.NoError(t, os.WriteFile(filepath.Join(goldenDir, "screen.golden"), []byte("┃ c?\n"), 0o644))
+
+	snapshots := []snapshot{
+		{question: "a?", plain: "┃ a?"},
+		{question: "b?", plain: "┃ b?"},
+	}
+
+	// Act
+	result := assertSnapshots(t, snapshots, goldenDir, screenOptions{}, true)
+
+	// Assert
+	assert.True(t, result)
+
+	entries, err := os.ReadDir(goldenDir)
+	require.NoError(t, err)
+
+	names := make([]string, len(entries))
+	for index, entry := range entries {
+		names[index] = entry.Name()
+	}
+
+	assert.Equal(t, []string{"01-a.golden", "02-b.golden", "notes.txt", "screen.golden"}, names)
+}
+
+func TestAssertSnapshots_ComparesScreensOfAnsweredQuestions(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?"),
+			huh.NewSelect[string]().Title("Favourite colour?").Options(huh.NewOptions("Red", "Green", "Blue")...),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().Title("Are you sure?"),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		AddSelect("Favourite colour?", 1).
+		AddConfirm("Are you sure?", ConfirmAffirm).
+		GuessConfirms()
+
+	RunForm(t, form, responder)
+
+	// Act
+	result := AssertSnapshots(t, responder, filepath.Join("testdata", "snapshots"))
+
+	// Assert
+	assert.True(t, result)
+}
+
+func TestAssertScreen_ComparesCurrentScreen(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("B?", "b")
+
+	stdin, stdout, closer := responder.Start(t, defaultTimeout)
+	defer closer()
+
+	_, err := stdout.Write([]byte("  A?\r\n  > a\r\n\r\n"))
+	require.NoError(t, err)
+
+	simulateCLI(t, []string{"┃ B?\r\n┃ >"}, stdout, stdin)
+
+	// Act
+	result := AssertScreen(t, responder, filepath.Join("testdata", "screen.golden"))
+
+	// Assert
+	assert.True(t, result)
+}
@@ -1,22 +1,26 @@
 package huhtest
 
 import (
-	"bufio"
+	"context"
+	"errors"
+	"fmt"
 	"io"
+	"io/fs"
+	"os"
 	"slices"
 	"strings"
+	"sync"
 	"time"
 
+	"github.com/charmbracelet/huh"
 	testingi "github.com/mitchellh/go-testing-interface"
 )
 
 // Reference: https://www.alanwood.net/demos/ansi.html
 const (
-	// defaultSubmit is appended to all responses to move to the next one. These represent \r\n.
-	defaultSubmit = "\x0D\x0A"
-
-	// selectSubmit is a special case where the defaultSubmit messes up the input in select statements
-	selectSubmit = "\x0D"
+	// defaultSubmit is appended to all responses to move to the next one. This represents \r, a \n
+	// should not be sent along with it as bubbletea reads it as ctrl+j, which moves down in select statements.
+	defaultSubmit = "\x0D"
 
 	// selectOption is used in a select and multiselect to mark or unmark an item
 	selectOption = "\x20"
@@ -24,16 +28,69 @@ const (
 	// arrowDown is used in a select and multiselect to move downwards
 	arrowDown = "\x1b[B"
 
+	// arrowUp is used in a select and multiselect to move upwards
+	arrowUp = "\x1b[A"
+
 	// arrowRight is used in a confirm to move between yes and no
 	arrowRight = "\x1b[C"
+
+	// arrowLeft is used in a file picker to go back to the parent directory
+	arrowLeft = "\x1b[D"
+
+	// newLine is used in a text field to start a new line, bubbletea reads it as ctrl+j
+	newLine = "\x0A"
+
+	// clearLine is used in an input to delete everything before the cursor, or in a text field on the current line
+	clearLine = "\x15"
+
+	// backspace is used in a text field to delete the start of a line, which joins it with the previous line
+	backspace = "\x7f"
+
+	// lineEnd is used in an input to move the cursor to the end of the text
+	lineEnd = "\x1b[F"
+
+	// inputEnd is used in a text field to move the cursor to the end of the last line, bubbletea reads it as alt+>
+	inputEnd = "\x1b>"
+
+	// filterOptions is used in a select and multiselect to start typing a filter
+	filterOptions = "/"
+
+	// listStart is used in a select to move the cursor to the first option, bubbletea reads it as home
+	listStart = "\x1b[H"
+
+	// acceptSuggestion is used in an input to complete the text using the suggestion that's shown, bubbletea reads
+	// it as ctrl+e
+	acceptSuggestion = "\x05"
 )
 
+// outputBufferSize is the amount of bytes read from the form's output at once
+const outputBufferSize = 64 * 1024
+
 // readableReplacer is used primarily for logging to represent awkward
 // characters with a readable representation
 var readableReplacer = strings.NewReplacer(
+	escapeKey, "<esc>",
 	defaultSubmit, "<submit>",
 	arrowDown, "<down>",
+	arrowUp, "<up>",
+	keySeparator, "",
+	pasteStart, "",
+	pasteEnd, "",
 	arrowRight, "<right>",
+	newLine, "<newline>",
+	clearLine, "<clear>",
+	backspace, "<backspace>",
+	lineEnd, "<end>",
+	inputEnd, "<input-end>",
+	listStart, "<home>",
+	acceptSuggestion, "<complete>",
+	string(KeyTab), "<tab>",
+	string(KeyShiftTab), "<shift+tab>",
+	string(KeyCtrlC), "<ctrl+c>",
+	string(KeyDelete), "<delete>",
+	arrowLeft, "<left>",
+	string(KeyPageUp), "<pgup>",
+	string(KeyPageDown), "<pgdown>",
 )
 
 // NewResponder instantiates a Responder that allows you to build responses
@@ -57,6 +114,7 @@ func NewResponder() *Responder {
 		latestQuestionMatchType: defaultQuestionMatchType,
 		latestResponse:          new(response),
 
+		screen:    newScreen(),
 		responses: newResponses(),
 	}
 }
@@ -88,7 +146,41 @@ type Responder struct {
 	// debug can be flipped to increase debugging in the Start method
 	debug bool
 
-	responses *responses
+	// strict makes the Start method fail the test if a focused field doesn't match any response
+	strict bool
+
+	// failOnAmbiguousMatch makes the test fail if a question matches more than one response
+	failOnAmbiguousMatch bool
+
+	// autoAdvanceNotes makes the Start method move past focused notes that don't match any response
+	autoAdvanceNotes bool
+
+	// guessConfirms makes the Start method answer confirms whose focused button can't be read with a warning
+	guessConfirms bool
+
+	// expectedErrors are checked in AssertExpectations, they're guarded by responsesLock
+	expectedErrors []*expectedValidationError
+
+	// keyMap is used to derive the keystrokes sent to the form, the default huh keys are used if nil
+	keyMap *huh.KeyMap
+
+	// fileSystem contains the files shown in file pickers, the working directory is used if nil
+	fileSystem fs.FS
+
+	// screen contains everything the form has rendered after Start is called, screenLock guards it
+	// as it's written to in a separate goroutine.
+	screen     *screen
+	screenLock sync.Mutex
+
+	// responses are used in a separate goroutine after Start is called, responsesLock guards them
+	responses     *responses
+	responsesLock sync.Mutex
+
+	// transcript contains everything the Responder saw and did after Start is called, regardless of debug
+	transcript transcript
+
+	// snapshots contain the screen at every question that was answered, screenLock guards them as well
+	snapshots []snapshot
 }
 
 /**
@@ -113,12 +205,121 @@ func (r *Responder) addResponses(question string, answers ...string) *Responder
 	r.saveResponse()
 
 	r.latestQuestion = question
+	r.latestResponse.field = fieldInput
 	r.latestResponse.answers = append(r.latestResponse.answers, answers...)
 
 	return r
 }
 
-// AddSelect adds a response that will navigate a multiple-choice list and pick the index of the given option.
+// AddResponseWithSuggestion adds a response to a huh.Input with Suggestions that types the prefix and accepts the
+// suggestion that's shown for it. The field is only submitted if the input shows the expected completion afterwards,
+// otherwise the test will error with the value that was shown instead.
+//
+// If the same question comes up multiple times, the same response will be returned by default. Use Times()
+// or Once() to modify this behaviour and register an error.
+func (r *Responder) AddResponseWithSuggestion(question string, prefix string, expectedCompletion string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldInput
+	r.latestResponse.answers = append(r.latestResponse.answers, joinSuggestion(prefix, expectedCompletion))
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newSuggestionPick(answer), nil
+	}
+
+	return r
+}
+
+// AddKeys adds a response that sends the keys exactly as they are, to any type of field. Nothing is added to submit
+// the field, so end with KeyEnter or the key that's bound to move on. Use Keys to combine keys and text, for example:
+//
+//	AddKeys("Name?", Keys("foo", KeyTab, KeyDown, KeyEnter))
+//
+// If the same question comes up multiple times, the same response will be returned by default. Use Times()
+// or Once() to modify this behaviour and register an error.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddKeys(question string, keys Key) *Responder {
+	return r.addKeys(question, keys)
+}
+
+// addKeys adds responses that send the keys exactly as they are. If the same question comes up multiple times, the
+// next response in the list will be picked. If we run out of responses, the last response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addKeys(question string, keys ...Key) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldKeystrokes
+
+	for _, key := range keys {
+		r.latestResponse.answers = append(r.latestResponse.answers, string(key))
+	}
+
+	return r
+}
+
+// AbortAt makes the Responder quit the form once the question comes up, using the Quit binding of the key map,
+// which makes the form return huh.ErrUserAborted. Questions that come up after that aren't answered and fail the
+// test, use AssertFormAborted to run the form and check that it was aborted.
+func (r *Responder) AbortAt(question string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldAbort
+	r.latestResponse.answers = append(r.latestResponse.answers, "")
+	r.latestResponse.submitCharacterOverride = string(KeyCtrlC)
+
+	return r
+}
+
+// AddText adds a response to a huh.Text field that will be typed into the field. Lines in the answer are separated by
+// \n, which is sent as the keystroke that starts a new line before the field is submitted. If the same question
+// comes up multiple times, the same response will be returned by default. Use Times() or Once() to modify this
+// behaviour and register an error.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddText(question string, answer string) *Responder {
+	return r.addTexts(question, answer)
+}
+
+// addTexts adds multi-line responses to a huh.Text field. If the same question comes up multiple times, the next
+// response in the list will be picked. If we run out of responses, the last response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addTexts(question string, answers ...string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldText
+
+	for _, answer := range answers {
+		// A \r would submit the field halfway through, so Windows line endings are replaced
+		r.latestResponse.answers = append(r.latestResponse.answers, strings.ReplaceAll(answer, "\r\n", newLine))
+	}
+
+	return r
+}
+
+// AddAcceptDefault adds a response that submits a field without changing it, which keeps the value that the field
+// was given. This works for any type of field, the key to submit it is picked once the field is shown, depending
+// on what it looks like.
+//
+// If the same question comes up multiple times, it's submitted every time by default. Use RespondOnce() or
+// RespondTimes() to modify this behaviour and register an error.
+func (r *Responder) AddAcceptDefault(question string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldAny
+	r.latestResponse.answers = append(r.latestResponse.answers, "")
+
+	return r
+}
+
+// AddSelect adds a response that will navigate a multiple-choice list and pick the index of the given option. The
+// cursor is moved to the first option and down from there, so that options a Height hides can be picked as well.
 // If the same question comes up multiple times, the same response will be returned by default. Use Times()
 // or Once() to modify this behaviour and register an error.
 //
@@ -136,17 +337,21 @@ func (r *Responder) addSelects(question string, options ...int) *Responder {
 	r.saveResponse()
 
 	r.latestQuestion = question
-	r.latestResponse.submitCharacterOverride = selectSubmit
+	r.latestResponse.field = fieldSelect
+	r.latestResponse.resolve = resolveSelectIndex
 
 	for _, optionIndex := range options {
-		r.latestResponse.answers = append(r.latestResponse.answers, strings.Repeat(arrowDown, optionIndex))
+		r.latestResponse.answers = append(r.latestResponse.answers, joinIndexes([]int{optionIndex}))
 	}
 
 	return r
 }
 
 // AddMultiSelect adds a response that will navigate a multiple-choice list and pick the indexes of the given options.
-// If the same question comes up multiple times, the same response will be returned by default. Use Times()
+// Options that are already selected but aren't given are deselected, so exactly the given options end up selected.
+// If an index is beyond the rendered options or the field's Limit doesn't allow selecting all of them, the test
+// will error. Only the options that are shown can be picked, so options that the field's Height hides below them
+// can't be, use AddMultiSelectFiltered for these. If the same question comes up multiple times, the same response will be returned by default. Use Times()
 // or Once() to modify this behaviour and register an error.
 //
 // Multiple answers to the same question can be added by repeating this call.
@@ -163,29 +368,213 @@ func (r *Responder) addMultiSelects(question string, options ...[]int) *Responde
 	r.saveResponse()
 
 	r.latestQuestion = question
-
-	var answer strings.Builder
+	r.latestResponse.field = fieldMultiSelect
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newToggleCheck(answer, pickIndexes), nil
+	}
 
 	for _, option := range options {
-		for index := range option[len(option)-1] + 1 {
-			if slices.Contains(option, index) {
-				answer.WriteString(selectOption)
-			}
+		r.latestResponse.answers = append(r.latestResponse.answers, joinIndexes(option))
+	}
 
-			answer.WriteString(arrowDown)
-		}
+	return r
+}
+
+// AddMultiSelectAll adds a response that will select every option of a multiple-choice list. If the field has a
+// Limit that's lower than the amount of options, the test will error.
+//
+// If the same question comes up multiple times, the same response will be returned by default. Use Times()
+// or Once() to modify this behaviour and register an error.
+func (r *Responder) AddMultiSelectAll(question string) *Responder {
+	return r.addMultiSelectPick(question, pickAll)
+}
+
+// AddMultiSelectNone adds a response that will deselect every option of a multiple-choice list.
+//
+// If the same question comes up multiple times, the same response will be returned by default. Use Times()
+// or Once() to modify this behaviour and register an error.
+func (r *Responder) AddMultiSelectNone(question string) *Responder {
+	return r.addMultiSelectPick(question, pickNone)
+}
+
+// addMultiSelectPick adds a response that will select exactly the options that pick returns for a multiple-choice
+// list, which doesn't depend on an answer.
+func (r *Responder) addMultiSelectPick(question string, pick func(answer string, options []option) ([]int, error)) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldMultiSelect
+	r.latestResponse.answers = append(r.latestResponse.answers, "")
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newToggleCheck(answer, pick), nil
+	}
+
+	return r
+}
+
+// AddSelectLabel adds a response that will navigate a multiple-choice list and pick the option with the given label.
+// Unlike AddSelect, the option list is read from the form's output, so reordering the options won't break the test.
+// If the label can't be found, the test will error with a list of the available labels.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddSelectLabel(question string, label string) *Responder {
+	return r.addSelectLabels(question, label)
+}
 
-		// Remove trailing down arrows
-		input := strings.TrimSuffix(answer.String(), arrowDown)
+// addSelectLabels adds a response that will navigate a multiple-choice list and pick the option with the given label.
+// If the same question comes up multiple times, the next response in the list will be picked. If we
+// run out of responses, the last response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addSelectLabels(question string, labels ...string) *Responder {
+	r.saveResponse()
 
-		r.latestResponse.answers = append(r.latestResponse.answers, input)
+	r.latestQuestion = question
+	r.latestResponse.field = fieldSelect
+	r.latestResponse.resolve = resolveSelectLabel
+	r.latestResponse.answers = append(r.latestResponse.answers, labels...)
 
-		answer.Reset()
+	return r
+}
+
+// AddMultiSelectLabels adds a response that will navigate a multiple-choice list and pick the options with the given labels.
+// Unlike AddMultiSelect, the option list is read from the form's output, so reordering the options won't break the test.
+// Like AddMultiSelect, exactly the options with the given labels end up selected. If any of the labels can't be found,
+// the test will error with a list of the available labels.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddMultiSelectLabels(question string, labels []string) *Responder {
+	return r.addMultiSelectLabels(question, labels)
+}
+
+// addMultiSelectLabels adds a response that will navigate a multiple-choice list and pick the options with the given labels.
+// If the same question comes up multiple times, the next response in the list will be picked. If we
+// run out of responses, the last response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addMultiSelectLabels(question string, labels ...[]string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldMultiSelect
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newToggleCheck(answer, pickLabels), nil
+	}
+
+	for _, option := range labels {
+		r.latestResponse.answers = append(r.latestResponse.answers, strings.Join(option, labelSeparator))
 	}
 
 	return r
 }
 
+// AddSelectFiltered adds a response that will type the filter text into a select and pick the option that remains,
+// which is faster than navigating to an option in a long list. The filter has to leave exactly one option, or an
+// option with the filter text as its label, otherwise the test will error with a list of the options that remain.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddSelectFiltered(question string, filterText string) *Responder {
+	return r.addSelectsFiltered(question, filterText)
+}
+
+// addSelectsFiltered adds responses that will filter a select and pick the option that remains. If the same question
+// comes up multiple times, the next response in the list will be picked. If we run out of responses, the last
+// response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addSelectsFiltered(question string, filterTexts ...string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldSelect
+	r.latestResponse.answers = append(r.latestResponse.answers, filterTexts...)
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newFilterPick(answer, false), nil
+	}
+
+	return r
+}
+
+// AddMultiSelectFiltered adds a response that will type every filter text into a multi-select and select the option
+// that remains, like AddSelectFiltered. Options that are already selected stay selected, as the options that don't
+// match a filter can't be seen.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddMultiSelectFiltered(question string, filterTexts []string) *Responder {
+	return r.addMultiSelectsFiltered(question, filterTexts)
+}
+
+// addMultiSelectsFiltered adds responses that will filter a multi-select and select the options that remain. If the
+// same question comes up multiple times, the next response in the list will be picked. If we run out of responses,
+// the last response will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addMultiSelectsFiltered(question string, filterTexts ...[]string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldMultiSelect
+
+	for _, filters := range filterTexts {
+		r.latestResponse.answers = append(r.latestResponse.answers, strings.Join(filters, labelSeparator))
+	}
+
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		return newFilterPick(answer, true), nil
+	}
+
+	return r
+}
+
+// AddFilePick adds a response that will navigate a huh.FilePicker to the given path and pick it. The path is relative
+// to the directory the file picker starts in and is looked up in the file system given to WithFileSystem, as the file
+// picker only shows one directory at a time. If the path can't be found, the test will error with a list of the
+// available files.
+//
+// Multiple answers to the same question can be added by repeating this call.
+func (r *Responder) AddFilePick(question string, path string) *Responder {
+	return r.addFilePicks(question, path)
+}
+
+// addFilePicks adds responses that will navigate a huh.FilePicker to the given paths. If the same question comes
+// up multiple times, the next response in the list will be picked. If we run out of responses, the last response
+// will be returned.
+//
+// NOTICE: This method is currently not exported, might consider doing this later
+func (r *Responder) addFilePicks(question string, paths ...string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = question
+	r.latestResponse.field = fieldFilePicker
+	r.latestResponse.answers = append(r.latestResponse.answers, paths...)
+	r.latestResponse.steps = func(answer string) (stepper, error) {
+		fileSystem := r.fileSystem
+		if fileSystem == nil {
+			fileSystem = os.DirFS(".")
+		}
+
+		return newFilePick(fileSystem, answer)
+	}
+
+	return r
+}
+
+// AddNote adds a response that moves past a huh.Note with the given title. Notes only get focus if they're the only
+// field in their group, in which case they wait for a key press. Every line of the note is matched, so the description
+// can be used as well. Use AutoAdvanceNotes to move past notes without adding them one by one.
+//
+// If the same note comes up multiple times, it's advanced every time by default. Use RespondOnce() or RespondTimes()
+// to modify this behaviour and register an error.
+func (r *Responder) AddNote(title string) *Responder {
+	r.saveResponse()
+
+	r.latestQuestion = title
+	r.latestResponse.field = fieldNote
+	r.latestResponse.answers = append(r.latestResponse.answers, "")
+
+	return r
+}
+
 // ConfirmResponse could have been a boolean, but I wanted to be more semantic by using Affirm and Negative like huh does it.
 type ConfirmResponse string
 
@@ -200,7 +589,8 @@ const (
 // AddConfirm adds a confirm response to the Responder. If the same question comes up multiple times, the same response will be returned by default. Use Times()
 // or Once() to modify this behaviour and register an error.
 //
-// Multiple answers to the same question can be added by repeating this call.
+// Multiple answers to the same question can be added by repeating this call. The focused button is read from the
+// screen, see GuessConfirms for forms that are rendered without colours.
 func (r *Responder) AddConfirm(question string, answer ConfirmResponse) *Responder {
 	return r.addConfirms(question, answer)
 }
@@ -215,14 +605,11 @@ func (r *Responder) addConfirms(question string, answers ...ConfirmResponse) *Re
 	r.saveResponse()
 
 	r.latestQuestion = question
+	r.latestResponse.field = fieldConfirm
+	r.latestResponse.resolve = resolveConfirm
 
 	for _, answer := range answers {
-		switch answer {
-		case ConfirmNegative:
-			r.latestResponse.answers = append(r.latestResponse.answers, " ")
-		case ConfirmAffirm:
-			r.latestResponse.answers = append(r.latestResponse.answers, arrowRight+" ")
-		}
+		r.latestResponse.answers = append(r.latestResponse.answers, string(answer))
 	}
 
 	return r
@@ -278,16 +665,76 @@ func (r *Responder) RespondTimes(times int) *Responder {
 	return r
 }
 
+// ReplaceExisting makes the previous response delete the value of the field before typing the answer, by default
+// an answer is added to the value that an input or text field already has. This only affects answers that are typed,
+// like the ones added using AddResponse and AddText.
+func (r *Responder) ReplaceExisting() *Responder {
+	r.latestResponse.replaceExisting = true
+	return r
+}
+
+// OnValidationError makes the Responder answer again if the form rejects the previous response with a validation
+// error that contains the given message. The text that was typed is deleted and the answer is typed instead. Every
+// follow-up answer is used once, so the same message can be added again in case the follow-up is rejected as well.
+//
+// This only works for answers that are typed, like the ones added using AddResponse and AddText. If an answer is
+// rejected without a follow-up, the test fails and the readers and writers are closed.
+func (r *Responder) OnValidationError(message string, answer string) *Responder {
+	if r.latestResponse.field == fieldText {
+		answer = strings.ReplaceAll(answer, "\r\n", newLine)
+	}
+
+	r.latestResponse.followUps = append(r.latestResponse.followUps, &followUp{message: message, answer: answer})
+
+	return r
+}
+
+// GoBack makes the Responder move back the given amount of fields the first time the latest answer would be given,
+// after which the question gets its answer once it comes up again. Fields in between are passed without answering
+// them, and the field that's arrived at is answered by its own response. This makes it possible to change a previous
+// answer and check that fields using TitleFunc or OptionsFunc are updated accordingly.
+//
+// Fields are moved back one at a time using the Prev binding of the key map, also across groups. The fields that are
+// passed don't use their responses, but the go-back counts as a use of the latest one, see RespondTimes.
+func (r *Responder) GoBack(fields int) *Responder {
+	return r.addGoBack(goBack{fields: max(fields, 1)})
+}
+
+// GoBackTo works like GoBack, but moves back until a field gets focus with the given question in its title.
+func (r *Responder) GoBackTo(question string) *Responder {
+	return r.addGoBack(goBack{target: question})
+}
+
+// addGoBack inserts the goBack before the latest answer of the latest response
+func (r *Responder) addGoBack(back goBack) *Responder {
+	index := max(len(r.latestResponse.answers)-1, 0)
+	r.latestResponse.answers = slices.Insert(r.latestResponse.answers, index, joinGoBack(back))
+
+	return r
+}
+
+// ExpectValidationError makes AssertExpectations fail if the form didn't show a validation error containing the
+// given message while a field with the question in its title had focus. Use OnValidationError to answer the field
+// again once the error is shown.
+func (r *Responder) ExpectValidationError(question string, message string) *Responder {
+	r.expectedErrors = append(r.expectedErrors, &expectedValidationError{question: question, message: message})
+	return r
+}
+
 /**
  * 'Other' methods
  */
 
-// Closer is returned from Start and should be called in a defer after calling Start
+// Closer is returned from Start and StartContext and should be called in a defer after calling either of them.
+// It closes the readers and writers, stops the goroutines of the Responder and waits for them to exit.
 type Closer func()
 
-// Start will kick off the goroutine that will listen for inputs in the returned io.PipeWriter. It will
-// then attempt to answer the incoming line with a registered response on the io.PipeReader. You're required
-// to provide a timeout that will stop the reader and writer to prevent it from locking forever.
+// Start will kick off the goroutine that will listen for inputs in the returned io.PipeWriter. The output is
+// rendered on a virtual screen and whenever a new field gets focus, its title is matched against the registered
+// responses and the answer is written to the io.PipeReader. If the output doesn't contain a focused field, every
+// line that's written is matched instead. Focused notes are recognised as well, see AddNote. You're required to
+// provide a timeout that will stop the reader and writer to prevent it from locking forever, the test fails if it's
+// reached.
 //
 // To stop the responder, you can call the returned cancel/close function that will close the readers and
 // writers
@@ -304,68 +751,654 @@ type Closer func()
 func (r *Responder) Start(t testingi.T, timeout time.Duration) (*io.PipeReader, *io.PipeWriter, Closer) {
 	t.Helper()
 
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+
+	formStdIn, formStdOut, closer := r.StartContext(ctx, t)
+
+	return formStdIn, formStdOut, func() {
+		closer()
+		cancel()
+	}
+}
+
+// StartContext works like Start, but stops the reader and writer once the given context is done instead of after
+// a timeout. The test fails if the context's deadline is exceeded, cancelling it stops the Responder quietly.
+//
+// The returned Closer waits for all goroutines to exit, after which nothing is reported to the test anymore. It's
+// also registered using t.Cleanup, in case the test ends before it's called.
+func (r *Responder) StartContext(ctx context.Context, t testingi.T) (*io.PipeReader, *io.PipeWriter, Closer) {
+	t.Helper()
+
 	r.saveResponse()
 
+	var keys keyBindings
+
+	if r.keyMap != nil {
+		var err error
+
+		keys, err = newKeyBindings(r.keyMap)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+
 	formStdIn, answerInput := io.Pipe()
 	questionOutput, formStdOut := io.Pipe()
 
-	// Avoids having to put if-statements everywhere
-	log := func(input ...any) {
+	// done is closed once the Responder stops, after which the goroutines should not report anything
+	done := make(chan struct{})
+	var stopOnce sync.Once
+
+	stopped := func() bool {
+		select {
+		case <-done:
+			return true
+		default:
+			return false
+		}
+	}
+
+	// stop closes the readers and writers without waiting for the goroutines, so they can call it themselves. The
+	// form's input is closed before our end of it, as bubbletea ends its program with an error if reading fails, but
+	// keeps running forever if it reads io.EOF, which it would if our end was closed first. The form's output is
+	// closed before its input, so it can't be written to anymore once reading from the input fails.
+	stop := func() {
+		stopOnce.Do(func() {
+			close(done)
+
+			formStdOut.Close()
+			formStdIn.Close()
+
+			answerInput.Close()
+			questionOutput.Close()
+		})
+	}
+
+	var running sync.WaitGroup
+
+	closer := func() {
+		stop()
+		running.Wait()
+	}
+
+	// Cleanups run in reverse order, so the transcript is complete by the time this one runs
+	t.Cleanup(func() {
+		if t.Failed() {
+			t.Logf("The test failed, the transcript of the responder:\n%s\n\nThe screen looked like this:\n%s", r.Transcript(), r.Screen())
+		}
+	})
+
+	t.Cleanup(closer)
+
+	r.transcript.add(transcriptLog, "Started")
+
+	// reporting guards the calls to t from the goroutines, as not every testingi.T is safe for concurrent use
+	var reporting sync.Mutex
+
+	// fail reports an error, unless the Responder has been stopped and the test might be done already
+	fail := func(format string, args ...any) {
+		reporting.Lock()
+		defer reporting.Unlock()
+
+		if !stopped() {
+			r.transcript.add(transcriptError, fmt.Sprintf(format, args...))
+			t.Errorf(format, args...)
+		}
+	}
+
+	// warn adds a warning to the transcript and logs it, without failing the test
+	warn := func(format string, args ...any) {
+		reporting.Lock()
+		defer reporting.Unlock()
+
+		if !stopped() {
+			r.transcript.add(transcriptWarning, "Warning: "+fmt.Sprintf(format, args...))
+			t.Logf("Warning: "+format, args...)
+		}
+	}
+
+	// record adds an entry to the transcript, which is logged as well in debug mode
+	record := func(kind transcriptKind, input ...any) {
+		r.transcript.add(kind, strings.TrimSuffix(fmt.Sprintln(input...), "\n"))
+
+		reporting.Lock()
+		defer reporting.Unlock()
+
 		// If the test has already failed, we could cause a panic
-		if r.debug && !t.Failed() {
+		if r.debug && !t.Failed() && !stopped() {
 			t.Log(input...)
 		}
 	}
 
+	// Avoids having to put if-statements everywhere, everything that's logged also ends up in the transcript
+	log := func(input ...any) {
+		record(transcriptLog, input...)
+	}
+
+	// queued contains the parts of the input that haven't been written to the form yet, see splitInput. They're written
+	// by a separate goroutine, as the form doesn't read its input while it waits for us to read what it rendered.
+	var queued []string
+	var queueLock sync.Mutex
+	inputQueued := make(chan struct{}, 1)
+
+	// write queues the input to be sent to the form
+	write := func(input string) {
+		record(transcriptReply, "Replying:", readableReplacer.Replace(input))
+
+		queueLock.Lock()
+		queued = append(queued, splitInput(input)...)
+		queueLock.Unlock()
+
+		select {
+		case inputQueued <- struct{}{}:
+		default:
+		}
+	}
+
+	// dequeue returns the next part of the input to write, if any
+	dequeue := func() (string, bool) {
+		queueLock.Lock()
+		defer queueLock.Unlock()
+
+		if len(queued) == 0 {
+			return "", false
+		}
+
+		part := queued[0]
+		queued = queued[1:]
+
+		return part, true
+	}
+
+	// pending contains the response that is being answered in steps, if any
+	var pending *pendingSteps
+
+	// answeredBy is the response that was sent to the latest question and answeredWith the answer that was picked
+	var answeredBy *response
+	var answeredWith string
+
+	// goingBack is set while the Responder moves back to a previous field, see Responder.GoBack
+	var goingBack *goBack
+
+	// aborted is set once the form has been quit, see Responder.AbortAt
+	var aborted bool
+
+	// step sends the next step of the pending response for the current lines of its field
+	step := func(field []string) {
+		input, last, err := pending.steps.next(field)
+		if err != nil {
+			fail("%s, closing readers and writers. The screen looked like this:\n%s", err, r.Screen())
+			stop()
+
+			pending = nil
+
+			return
+		}
+
+		if input != "" {
+			write(keys.replace(pending.response, input))
+		}
+
+		if last {
+			pending = nil
+		}
+	}
+
+	// awaitsOptions returns true if the question is answered using the options of a select or multi-select that aren't
+	// shown yet, because they're loaded using OptionsFunc
+	awaitsOptions := func(question string, field []string) bool {
+		if isLoading(field) {
+			return true
+		}
+
+		r.responsesLock.Lock()
+		response, _, ok := r.responses.find(question)
+		r.responsesLock.Unlock()
+
+		if !ok || (response.field != fieldSelect && response.field != fieldMultiSelect) {
+			return false
+		}
+
+		return !isInlineSelect(field) && len(parseOptions(field)) == 0
+	}
+
+	// respond looks for a response to the question and sends it, the field is used for answers that depend
+	// on what's rendered. Returns whether the question matched a response.
+	respond := func(question string, field fieldView) bool {
+		r.responsesLock.Lock()
+		response, matched, ok := r.responses.find(question)
+
+		if !ok {
+			r.responsesLock.Unlock()
+			return false
+		}
+
+		record(transcriptMatch, "Matches question:", matched)
+
+		if aborted {
+			r.responsesLock.Unlock()
+			fail("Question %q came up after the form was aborted, closing readers and writers", question)
+			stop()
+
+			return true
+		}
+
+		if r.failOnAmbiguousMatch {
+			if err := r.responses.checkAmbiguity(question); err != nil {
+				fail("%s", err)
+			}
+		}
+
+		answer, err := response.pickAnswer()
+		r.responsesLock.Unlock()
+
+		if err != nil {
+			fail("%s", err)
+		}
+
+		r.snapshot(question)
+
+		if back, ok := parseGoBack(answer); ok {
+			answeredBy, answeredWith = nil, ""
+
+			back.previous = keys.previous(response)
+			goingBack = &back
+
+			log("Going back")
+			write(back.previous)
+			goingBack.fields--
+
+			return true
+		}
+
+		answeredBy, answeredWith = response, answer
+
+		if response.field == fieldAbort {
+			log("Aborting form")
+			aborted = true
+		}
+
+		if response.steps != nil {
+			steps, err := response.steps(answer)
+			if err != nil {
+				fail("%q: %s, closing readers and writers. The screen looked like this:\n%s", question, err, r.Screen())
+				stop()
+
+				return true
+			}
+
+			pending = &pendingSteps{response: response, steps: steps}
+			step(field.lines)
+
+			return true
+		}
+
+		if response.resolve != nil {
+			answer, err = response.resolve(answer, field)
+
+			// A guess can't be checked, so it fails the test unless it's allowed
+			if errors.Is(err, errFocusGuessed) && r.guessConfirms {
+				warn("%q: %s", question, err)
+				err = nil
+			}
+
+			if err != nil {
+				fail("%q: %s, closing readers and writers. The screen looked like this:\n%s", question, err, r.Screen())
+				stop()
+
+				return true
+			}
+		}
+
+		// A response to any type of field is sent using the keys of the field that's focused
+		if response.field == fieldAny {
+			focused := *response
+			focused.field = focusedFieldType(field)
+			response = &focused
+		}
+
+		write(keys.translate(response, answer))
+
+		return true
+	}
+
+	// followUp answers the question again after the form has rejected the answer with the given validation errors
+	followUp := func(question string, validationErrors []string) {
+		validationError := strings.Join(validationErrors, "\n")
+
+		log("Got validation error:", validationError)
+
+		r.responsesLock.Lock()
+		for _, expected := range r.expectedErrors {
+			expected.observe(question, validationError)
+		}
+
+		answer, err := answeredBy.followUp(validationError)
+		r.responsesLock.Unlock()
+
+		var erase string
+		if err == nil {
+			erase, err = clearText(answeredBy.field, answeredWith)
+		}
+
+		if err != nil {
+			fail("%q: %s, closing readers and writers. The screen looked like this:\n%s", question, err, r.Screen())
+			stop()
+
+			return
+		}
+
+		answeredWith = answer
+
+		write(erase + keys.translate(answeredBy, answer))
+	}
+
+	running.Add(3)
+
+	go func() {
+		defer running.Done()
+
+		for {
+			select {
+			case <-done:
+				return
+			case <-inputQueued:
+			}
+
+			for part, ok := dequeue(); ok; part, ok = dequeue() {
+				if _, err := answerInput.Write([]byte(part)); err != nil {
+					fail("%s", err)
+					return
+				}
+			}
+		}
+	}()
+
 	go func() {
-		lineReader := bufio.NewScanner(questionOutput)
+		defer running.Done()
+
+		buffer := make([]byte, outputBufferSize)
+
+		var answered *answeredField
+
+		// seenField is set once a focused field has been rendered, after which the output is known to be a huh form
+		var seenField bool
+
+		// rejected contains the lines of the answered field and its validation errors when they were followed up on
+		var rejected []string
+
+		for {
+			n, err := questionOutput.Read(buffer)
+			if err != nil {
+				return
+			}
+
+			r.screenLock.Lock()
+			_, _ = r.screen.Write(buffer[:n])
+			changed := r.screen.changedLines()
+			rendered := r.screen.lines()
+			frame := r.screen.String()
+			field, top, ok := r.screen.focusedField()
+			styles := r.screen.fieldStyles(top, len(field))
+			note, noteTop, isNote := r.screen.focusedNote()
+			validationErrors := r.screen.validationErrors(top)
+			r.screenLock.Unlock()
+
+			r.transcript.add(transcriptFrame, frame)
+
+			// A focused note doesn't have a title like other fields, so every line is matched until one of them does
+			if isNote {
+				seenField = true
+				pending = nil
+
+				if !answered.asks(fieldView{lines: note}, noteTop, rendered) {
+					continue
+				}
+
+				log("Got focused note:", strings.Join(note, "\n"))
+
+				if goingBack != nil {
+					if !slices.ContainsFunc(note, func(line string) bool { return goingBack.arrived(strings.TrimSpace(line)) }) {
+						write(goingBack.previous)
+						goingBack.fields--
+
+						answered = newAnsweredField(noteTop, fieldView{lines: note}, rendered)
+						continue
+					}
+
+					log("Went back to note:", strings.TrimSpace(note[0]))
+					goingBack = nil
+				}
 
-		for lineReader.Scan() {
-			line := lineReader.Text()
+				var matched bool
 
-			log("Got line:", line)
+				for _, line := range note {
+					if matched = respond(strings.TrimSpace(line), fieldView{lines: note, note: true}); matched {
+						break
+					}
+				}
+
+				if !matched && r.autoAdvanceNotes {
+					log("Advancing note")
+					write(keys.translate(&response{field: fieldNote}, ""))
+
+					matched = true
+				}
+
+				if matched {
+					answered = newAnsweredField(noteTop, fieldView{lines: note}, rendered)
+					continue
+				}
+
+				if r.strict {
+					fail("No response matches note %q, closing readers and writers. The screen looked like this:\n%s", strings.TrimSpace(note[0]), r.Screen())
+					stop()
+
+					return
+				}
+
+				continue
+			}
+
+			// Without a focused field or note, this doesn't look like a huh form, so we fall back to
+			// matching every line that has been written to. Once a form has been seen, frames without
+			// focus are only rendered after it's done, so there's nothing left to answer.
+			if !ok {
+				if seenField {
+					continue
+				}
 
-			response, question, ok := r.responses.find(line)
+				for _, line := range changed {
+					log("Got line:", line)
+					respond(line, fieldView{lines: []string{line}})
+				}
+
+				continue
+			}
+
+			seenField = true
+
+			// A field that's answered in steps gets the next step whenever it's rendered, until it's done or another
+			// field gets focus.
+			if pending != nil && answered.sameField(field, top) {
+				step(field)
+
+				// The title of a select is replaced by its filter while filtering, so the title it was answered for is kept.
+				// Until the answer has been submitted, the select may still show the filter after the title.
+				if pending == nil {
+					title := answered.title
+					answered = newAnsweredField(top, fieldView{lines: field, styles: styles}, rendered)
+					answered.title = title
+				}
+
+				continue
+			}
 
-			if ok {
-				log("Matches question:", question)
+			pending = nil
 
-				answer, err := response.pickAnswer()
-				if err != nil {
-					t.Error(err)
+			// A rejected answer keeps the field focused and shows a validation error until the next key press. Frames
+			// are skipped if the form is fast enough, so a follow-up that's rejected as well is recognised by the
+			// field or the error looking different.
+			if answered.sameField(field, top) && answeredBy != nil && len(validationErrors) > 0 {
+				if frame := append(slices.Clone(field), validationErrors...); !slices.Equal(frame, rejected) {
+					rejected = frame
+					answered = newAnsweredField(top, fieldView{lines: field, styles: styles}, rendered)
+
+					followUp(fieldTitle(field[0]), validationErrors)
 				}
 
-				answer += response.submitCharacter()
+				continue
+			}
+
+			view := fieldView{lines: field, styles: styles}
+
+			if !answered.asks(view, top, rendered) {
+				continue
+			}
+
+			question := fieldTitle(field[0])
+
+			if awaitsOptions(question, field) {
+				continue
+			}
+
+			log("Got focused field:", strings.Join(field, "\n"))
 
-				log("Replying:", readableReplacer.Replace(answer))
+			if goingBack != nil {
+				if !goingBack.arrived(question) {
+					write(goingBack.previous)
+					goingBack.fields--
 
-				_, err = answerInput.Write([]byte(answer))
-				if err != nil {
-					t.Error(err)
+					answered = newAnsweredField(top, view, rendered)
+					continue
 				}
 
+				log("Went back to question:", question)
+				goingBack = nil
+			}
+
+			if respond(question, view) {
+				answered = newAnsweredField(top, view, rendered)
 				continue
 			}
+
+			if r.strict {
+				fail("No response matches question %q, closing readers and writers. The screen looked like this:\n%s", question, r.Screen())
+				stop()
+
+				return
+			}
 		}
 	}()
 
-	closer := func() {
-		answerInput.Close()
-		questionOutput.Close()
+	go func() {
+		defer running.Done()
 
-		formStdIn.Close()
-		formStdOut.Close()
-	}
+		select {
+		case <-ctx.Done():
+			if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+				fail("Deadline reached, closing readers and writers")
+			}
 
-	go func() {
-		time.Sleep(timeout)
-		t.Error("Deadline reached, closing readers and writers")
-		closer()
+			stop()
+		case <-done:
+		}
 	}()
 
 	return formStdIn, formStdOut, closer
 }
 
+// WithKeyMap makes the Responder use the keystrokes bound in the given huh.KeyMap, this should be the
+// same key map that's given to the form using huh.Form.WithKeyMap. For every binding the first key
+// is used, so make sure that the keys you want the Responder to use come first.
+func (r *Responder) WithKeyMap(keyMap *huh.KeyMap) *Responder {
+	r.keyMap = keyMap
+	return r
+}
+
+// WithFileSystem sets the files that file pickers show, which is used to navigate them in AddFilePick. This should be
+// the directory given to huh.FilePicker.CurrentDirectory, like os.DirFS(directory). The file picker itself always
+// reads from disk, so the files should exist there. By default, the working directory is used.
+func (r *Responder) WithFileSystem(fileSystem fs.FS) *Responder {
+	r.fileSystem = fileSystem
+	return r
+}
+
+// AssertExpectations fails the test if any of the responses has been used fewer times than expected. Responses
+// that have been given RespondOnce or RespondTimes should have been used exactly that many times, others at least
+// once. This should be called after the form is done, it lists every question that hasn't been used enough.
+func (r *Responder) AssertExpectations(t testingi.T) {
+	t.Helper()
+
+	r.saveResponse()
+
+	r.responsesLock.Lock()
+	defer r.responsesLock.Unlock()
+
+	if err := r.responses.verify(); err != nil {
+		t.Errorf("Not all responses have been used:\n%s", err)
+	}
+
+	errs := make([]error, len(r.expectedErrors))
+	for index, expected := range r.expectedErrors {
+		errs[index] = expected.verify()
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		t.Errorf("Not all expected validation errors have been shown:\n%s", err)
+	}
+}
+
+// Screen returns the output of the form as it would currently be displayed in a terminal, without any
+// styling. This can be used to make assertions on what the form looks like.
+func (r *Responder) Screen() string {
+	r.screenLock.Lock()
+	defer r.screenLock.Unlock()
+
+	return r.screen.String()
+}
+
+// Transcript returns everything the Responder has seen and done after Start was called, like the frames it has
+// received, the questions it has matched and the answers it has sent, along with the time since it was started. It
+// contains the same messages as Debug, but they're recorded even if it's off. If the test fails, the transcript is
+// logged automatically once the test is done.
+func (r *Responder) Transcript() string {
+	return r.transcript.String()
+}
+
+// Strict makes the test fail immediately if a field gets focus and none of the responses match its question,
+// instead of waiting for the timeout. The error contains the question and the screen at that moment, and
+// the readers and writers are closed, which makes the form return an error.
+func (r *Responder) Strict() *Responder {
+	r.strict = true
+	return r
+}
+
+// AutoAdvanceNotes makes the Responder move past every focused huh.Note that doesn't match any of the responses,
+// instead of waiting for the timeout. Notes that match a response added using AddNote are still counted, so
+// RespondOnce and RespondTimes keep working.
+func (r *Responder) AutoAdvanceNotes() *Responder {
+	r.autoAdvanceNotes = true
+	return r
+}
+
+// GuessConfirms makes the Responder answer confirms whose focused button can't be read from the screen, like when
+// the form is rendered without colours, by assuming that the negative button is focused like it is by default. A
+// warning is added to the transcript, as the answer is wrong if the value of the confirm starts out as true. Without
+// it, those confirms fail the test.
+func (r *Responder) GuessConfirms() *Responder {
+	r.guessConfirms = true
+	return r
+}
+
+// FailOnAmbiguousMatch makes the test fail if a question matches more than one of the registered questions. By
+// default the first match is used, exact matches come before substring matches and those come before regexp
+// matches. Questions of the same type are matched in the order they were registered.
+func (r *Responder) FailOnAmbiguousMatch() *Responder {
+	r.failOnAmbiguousMatch = true
+	return r
+}
+
 // Debug turns on logging for debugging forms
 func (r *Responder) Debug() *Responder {
 	r.debug = true
@@ -2,6 +2,8 @@ package huhtest
 
 import (
 	"bufio"
+	"context"
+	"fmt"
 	"io"
 	"strings"
 	"testing"
@@ -28,7 +30,8 @@ func simulateCLI(t *testing.T, questions []string, stdout *io.PipeWriter, stdin
 	for index, question := range questions {
 		t.Logf("Posing question: %s", question)
 
-		_, err := stdout.Write([]byte(question + defaultSubmit))
+		// Questions are followed by an empty line, so that consecutive fields don't end up as one
+		_, err := stdout.Write([]byte(question + "\r\n\r\n"))
 		require.NoError(t, err)
 
 		line, err := reader.ReadString('\r')
@@ -46,6 +49,30 @@ func simulateCLI(t *testing.T, questions []string, stdout *io.PipeWriter, stdin
 	return actualAnswers
 }
 
+// cleanupT is a testingi.T that records what's logged and runs its cleanups once runCleanups is called, unlike
+// testingi.RuntimeT which ignores them.
+type cleanupT struct {
+	testingi.RuntimeT
+
+	cleanups []func()
+	logs     []string
+}
+
+func (t *cleanupT) Cleanup(cleanup func()) {
+	t.cleanups = append(t.cleanups, cleanup)
+}
+
+func (t *cleanupT) Logf(format string, args ...any) {
+	t.logs = append(t.logs, fmt.Sprintf(format, args...))
+}
+
+// runCleanups runs the cleanups in reverse order, like testing.T does once a test is done
+func (t *cleanupT) runCleanups() {
+	for index := len(t.cleanups) - 1; index >= 0; index-- {
+		t.cleanups[index]()
+	}
+}
+
 // Tests
 
 const defaultTimeout = 1 * time.Second
@@ -86,23 +113,38 @@ func TestResponder_Start_ReturnsExpectedAnswers(t *testing.T) {
 			expectedAnswers: []string{"Yes, for sure!", "Yes, for sure!", "Yes, for sure!"},
 		},
 
+		"one multi-line text question": {
+			responder: NewResponder().
+				AddText("story?", "Once upon a time\nThe end"),
+			questions:       []string{"Tell me a story?"},
+			expectedAnswers: []string{"Once upon a time<newline>The end"},
+		},
+		"text question with windows line endings": {
+			responder: NewResponder().
+				addTexts("story?", "a\r\nb", "c"),
+			questions:       []string{"Tell me a story?", "Another story?"},
+			expectedAnswers: []string{"a<newline>b", "c"},
+		},
 		"one affirmative confirm question": {
 			responder: NewResponder().
-				addConfirms("alright?", ConfirmAffirm),
+				addConfirms("alright?", ConfirmAffirm).
+				GuessConfirms(),
 			questions:       []string{"You doing alright?"},
-			expectedAnswers: []string{"<right> "},
+			expectedAnswers: []string{"<right>"},
 		},
 		"one negative confirm question": {
 			responder: NewResponder().
-				addConfirms("alright?", ConfirmNegative),
+				addConfirms("alright?", ConfirmNegative).
+				GuessConfirms(),
 			questions:       []string{"You doing alright?"},
-			expectedAnswers: []string{" "},
+			expectedAnswers: []string{""},
 		},
 		"multiple confirm questions": {
 			responder: NewResponder().
-				addConfirms("right?", ConfirmAffirm, ConfirmNegative, ConfirmNegative),
+				addConfirms("right?", ConfirmAffirm, ConfirmNegative, ConfirmNegative).
+				GuessConfirms(),
 			questions:       []string{"You doing alright?", "is it alright?", "right?"},
-			expectedAnswers: []string{"<right> ", " ", " "},
+			expectedAnswers: []string{"<right>", "", ""},
 		},
 
 		"one select question": {
@@ -138,6 +180,35 @@ func TestResponder_Start_ReturnsExpectedAnswers(t *testing.T) {
 			},
 		},
 
+		"one select label question": {
+			responder: NewResponder().
+				AddSelectLabel("how?", "c"),
+			questions:       []string{"┃ how?\r\n┃ > a\r\n┃   b\r\n┃   c"},
+			expectedAnswers: []string{"<down><down>"},
+		},
+		"multiple select label questions": {
+			responder: NewResponder().
+				addSelectLabels("how?", "a", "c"),
+			questions: []string{
+				"┃ how?\r\n┃   a\r\n┃ > b\r\n┃   c",
+				"┃ how?\r\n┃ > a\r\n┃   b\r\n┃   c",
+			},
+			expectedAnswers: []string{"<up>", "<down><down>"},
+		},
+
+		"one keys question": {
+			responder: NewResponder().
+				AddKeys("how?", Keys("foo", KeyTab, KeyDown, KeyEnter)),
+			questions:       []string{"how?"},
+			expectedAnswers: []string{"foo<tab><down>"},
+		},
+		"multiple keys questions": {
+			responder: NewResponder().
+				addKeys("how?", Keys(KeyLeft, KeyEnter), Keys(Paste("up"), KeyEnter)),
+			questions:       []string{"how?", "how?"},
+			expectedAnswers: []string{"<left>", "up"},
+		},
+
 		"one exact match": {
 			responder: NewResponder().
 				AddResponse("You doing alright?", "Splendid").
@@ -265,6 +336,252 @@ func TestResponder_Start_CancelFunctionClosesPipes(t *testing.T) {
 	require.ErrorIs(t, writeErr, io.ErrClosedPipe)
 }
 
+func TestResponder_Start_CloserStopsTimeout(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder()
+
+	dummyT := new(testingi.RuntimeT)
+
+	_, _, closer := responder.Start(dummyT, 10*time.Millisecond)
+
+	// Act
+	closer()
+
+	// Assert
+	time.Sleep(50 * time.Millisecond)
+
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+}
+
+func TestResponder_StartContext_DeadlineClosesPipesAndFailsTest(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 0)
+	defer cancel()
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	formInput, formOutput, closer := responder.StartContext(ctx, dummyT)
+
+	// Assert
+	defer closer()
+
+	_, readErr := formInput.Read([]byte{})
+	require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+	_, writeErr := formOutput.Write([]byte{})
+	require.ErrorIs(t, writeErr, io.ErrClosedPipe)
+
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestResponder_StartContext_CancelClosesPipesWithoutFailing(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	dummyT := new(testingi.RuntimeT)
+
+	formInput, formOutput, closer := responder.StartContext(ctx, dummyT)
+	defer closer()
+
+	// Act
+	cancel()
+
+	// Assert
+	_, readErr := formInput.Read([]byte{})
+	require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+	_, writeErr := formOutput.Write([]byte{})
+	require.ErrorIs(t, writeErr, io.ErrClosedPipe)
+
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+}
+
+func TestResponder_StartContext_CloserDoesNotReportUnreadAnswers(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "a")
+
+	dummyT := new(testingi.RuntimeT)
+
+	_, stdout, closer := responder.StartContext(context.Background(), dummyT)
+
+	// Nobody reads the answer, so the Responder is stuck writing it
+	_, err := stdout.Write([]byte("a?\r\n"))
+	require.NoError(t, err)
+
+	// Act
+	closer()
+
+	// Assert
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+}
+
+func TestResponder_Start_ReadsOutputWhileWritingInput(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddKeys("a?", Keys("a", KeyEsc, "b"))
+
+	stdin, stdout, closer := responder.Start(t, defaultTimeout)
+	defer closer()
+
+	_, err := stdout.Write([]byte("a?\r\n"))
+	require.NoError(t, err)
+
+	// A lone escape character ends a write, so what follows it is written separately
+	buffer := make([]byte, 64)
+
+	n, err := stdin.Read(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, keySeparator+"a"+keySeparator+"\x1b", string(buffer[:n]))
+
+	// Act, a form doesn't read its input again until what it rendered has been read
+	_, err = stdout.Write([]byte("b?\r\n"))
+
+	// Assert
+	require.NoError(t, err)
+
+	n, err = stdin.Read(buffer)
+	require.NoError(t, err)
+	assert.Equal(t, "b"+keySeparator, string(buffer[:n]))
+}
+
+func TestResponder_Start_StopsAndFailsTestIfAnswerCantBeResolved(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		responder *Responder
+		output    string
+	}{
+		"label that is not an option": {
+			responder: NewResponder().AddSelectLabel("how?", "d"),
+			output:    "┃ how?\r\n┃ > a\r\n┃   b\r\n┃   c\r\n\r\n",
+		},
+		"multi-select label that is not an option": {
+			responder: NewResponder().AddMultiSelectLabels("how?", []string{"d"}),
+			output:    "┃ how?\r\n┃ > • a\r\n┃   • b\r\n\r\n",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			dummyT := new(testingi.RuntimeT)
+
+			stdin, stdout, closer := testData.responder.Start(dummyT, defaultTimeout)
+			defer closer()
+
+			start := time.Now()
+
+			// Act
+			_, err := stdout.Write([]byte(testData.output))
+			require.NoError(t, err)
+
+			// Assert
+			// The form's input is closed before the Responder's end of it, so reading fails rather than returning io.EOF
+			_, readErr := stdin.Read(make([]byte, 1))
+			require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+			assert.Less(t, time.Since(start), defaultTimeout)
+			assert.True(t, dummyT.Failed(), "Test should have failed")
+		})
+	}
+}
+
+func TestResponder_Start_StrictFailsTestOnUnmatchedQuestion(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "a").
+		Strict()
+
+	dummyT := new(testingi.RuntimeT)
+
+	stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+	defer closer()
+
+	start := time.Now()
+
+	// Act
+	_, err := stdout.Write([]byte("┃ b?\r\n┃ >\r\n"))
+	require.NoError(t, err)
+
+	// Assert
+	_, readErr := stdin.Read(make([]byte, 1))
+	require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+	assert.Less(t, time.Since(start), defaultTimeout)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestResponder_Start_FailsTestOnQuestionAfterAbort(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AbortAt("a?").
+		AddResponse("b?", "b")
+
+	dummyT := new(testingi.RuntimeT)
+
+	stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+	defer closer()
+
+	_, err := stdout.Write([]byte("┃ a?\r\n┃ >\r\n"))
+	require.NoError(t, err)
+
+	abort := make([]byte, 1)
+	_, err = stdin.Read(abort)
+	require.NoError(t, err)
+
+	// Act
+	_, err = stdout.Write([]byte("\x1b[H\x1b[2J┃ b?\r\n┃ >\r\n"))
+	require.NoError(t, err)
+
+	// Assert
+	_, readErr := stdin.Read(make([]byte, 1))
+	require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+	assert.Equal(t, string(KeyCtrlC), string(abort))
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+	assert.NotContains(t, responder.Transcript(), "Replying: b")
+}
+
+func TestResponder_Start_StrictIgnoresLinesOutsideOfFocusedFields(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "a").
+		Strict()
+
+	questions := []string{"Some output", "a?"}
+	expectedAnswers := []string{"a"}
+
+	dummyT := new(testingi.RuntimeT)
+
+	stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+	defer closer()
+
+	// Act
+	_, err := stdout.Write([]byte(questions[0] + "\r\n"))
+	require.NoError(t, err)
+
+	actualAnswers := simulateCLI(t, questions[1:], stdout, stdin)
+
+	// Assert
+	assert.Equal(t, expectedAnswers, actualAnswers)
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+}
+
 func TestNewResponderWith_SetsExpectedRespones(t *testing.T) {
 	t.Parallel()
 	// Arrange
@@ -289,3 +606,207 @@ func TestNewResponderWith_SetsExpectedRespones(t *testing.T) {
 
 	assert.Equal(t, expectedAnswers, actualAnswers)
 }
+
+func TestResponder_Screen_ReturnsRenderedOutput(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("B?", "b")
+
+	stdin, stdout, closer := responder.Start(t, defaultTimeout)
+	defer closer()
+
+	_, err := stdout.Write([]byte("  A?\r\n  > a\r\n\r\n"))
+	require.NoError(t, err)
+
+	simulateCLI(t, []string{"┃ B?\r\n┃ >"}, stdout, stdin)
+
+	// Act
+	result := responder.Screen()
+
+	// Assert
+	assert.Equal(t, "  A?\n  > a\n\n┃ B?\n┃ >", result)
+}
+
+func TestResponder_AssertExpectations_FailsTestOnUnusedResponses(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		responder *Responder
+		questions []string
+
+		expectedFailed bool
+	}{
+		"all responses used": {
+			responder: NewResponder().
+				AddResponse("a?", "a").
+				AddResponse("b?", "b").RespondTimes(2),
+			questions:      []string{"a?", "b?", "b?"},
+			expectedFailed: false,
+		},
+		"response never used": {
+			responder: NewResponder().
+				AddResponse("a?", "a").
+				AddResponse("b?", "b"),
+			questions:      []string{"a?"},
+			expectedFailed: true,
+		},
+		"response used fewer times than expected": {
+			responder: NewResponder().
+				AddResponse("a?", "a").RespondTimes(2),
+			questions:      []string{"a?"},
+			expectedFailed: true,
+		},
+		"validation error not shown": {
+			responder: NewResponder().
+				AddResponse("a?", "a").
+				ExpectValidationError("a?", "too short"),
+			questions:      []string{"a?"},
+			expectedFailed: true,
+		},
+		"last response is used": {
+			responder: NewResponder().
+				AddResponse("a?", "a").
+				AddResponse("b?", "b"),
+			questions:      []string{"a?", "b?"},
+			expectedFailed: false,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			stdin, stdout, closer := testData.responder.Start(t, defaultTimeout)
+			defer closer()
+
+			simulateCLI(t, testData.questions, stdout, stdin)
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			testData.responder.AssertExpectations(dummyT)
+
+			// Assert
+			assert.Equal(t, testData.expectedFailed, dummyT.Failed())
+		})
+	}
+}
+
+func TestResponder_Start_FailOnAmbiguousMatchFailsTest(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		failOnAmbiguousMatch bool
+
+		expectedFailed bool
+	}{
+		"disabled": {
+			failOnAmbiguousMatch: false,
+			expectedFailed:       false,
+		},
+		"enabled": {
+			failOnAmbiguousMatch: true,
+			expectedFailed:       true,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			responder := NewResponder().
+				AddResponse("Name", "foo").
+				AddResponse("Project Name", "bar")
+
+			if testData.failOnAmbiguousMatch {
+				responder.FailOnAmbiguousMatch()
+			}
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+			defer closer()
+
+			actualAnswers := simulateCLI(t, []string{"Project Name?"}, stdout, stdin)
+
+			// Assert
+			assert.Equal(t, []string{"foo"}, actualAnswers)
+			assert.Equal(t, testData.expectedFailed, dummyT.Failed())
+		})
+	}
+}
+
+func TestResponder_Transcript_ContainsMatchesAndReplies(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "foo")
+
+	stdin, stdout, closer := responder.Start(t, defaultTimeout)
+	defer closer()
+
+	simulateCLI(t, []string{"┃ a?"}, stdout, stdin)
+
+	// Act
+	result := responder.Transcript()
+
+	// Assert
+	assert.Contains(t, result, "Received frame:\n┃ a?")
+	assert.Contains(t, result, "Matches question: a?")
+	assert.Contains(t, result, "Replying: foo<submit>")
+}
+
+func TestResponder_Start_LogsTranscriptOnFailure(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "a").
+		Strict()
+
+	dummyT := new(cleanupT)
+
+	stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+
+	_, err := stdout.Write([]byte("┃ b?\r\n┃ >\r\n"))
+	require.NoError(t, err)
+
+	_, readErr := stdin.Read(make([]byte, 1))
+	require.ErrorIs(t, readErr, io.ErrClosedPipe)
+
+	closer()
+
+	// Act
+	dummyT.runCleanups()
+
+	// Assert
+	require.Len(t, dummyT.logs, 1)
+
+	assert.Contains(t, dummyT.logs[0], "The test failed, the transcript of the responder:")
+	assert.Contains(t, dummyT.logs[0], "Received frame:\n┃ b?\n┃ >")
+	assert.Contains(t, dummyT.logs[0], `No response matches question "b?"`)
+	assert.Contains(t, dummyT.logs[0], "The screen looked like this:\n┃ b?\n┃ >")
+}
+
+func TestResponder_Start_DoesNotLogTranscriptOnSuccess(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddResponse("a?", "a")
+
+	dummyT := new(cleanupT)
+
+	stdin, stdout, closer := responder.Start(dummyT, defaultTimeout)
+
+	simulateCLI(t, []string{"┃ a?"}, stdout, stdin)
+
+	closer()
+
+	// Act
+	dummyT.runCleanups()
+
+	// Assert
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+	assert.Empty(t, dummyT.logs)
+}
@@ -1,9 +1,20 @@
 package huhtest
 
 import (
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/huh"
+	"github.com/charmbracelet/lipgloss"
+	testingi "github.com/mitchellh/go-testing-interface"
+	"github.com/muesli/termenv"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -129,6 +140,8 @@ func TestHuhTest_RespondsCorrectlyToQuestions(t *testing.T) {
 		AddConfirm("Would you like a drink?", ConfirmAffirm).
 		// Confirm question with a false answer
 		AddConfirm("meal?", ConfirmNegative).
+		// The confirms are rendered without colours, but their values start out as false
+		GuessConfirms().
 		// Select with first option
 		AddSelect("Make a choice", 0).
 		// Select with third option
@@ -164,3 +177,1665 @@ func TestHuhTest_RespondsCorrectlyToQuestions(t *testing.T) {
 
 	assert.Equal(t, expected, actual)
 }
+
+func TestHuhTest_SelectsOptionsByLabel(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualSelect      string
+		actualMultiSelect []string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Have you slept well?").
+				Options(
+					huh.NewOption("Well!", "well"),
+					huh.NewOption("Terribly!", "terrible"),
+					huh.NewOption("It was OK!", "ok"),
+				).
+				Value(&actualSelect),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("What are your favourite activities?").
+				Options(
+					huh.NewOption("Cycling", "bike"),
+					huh.NewOption("Sleeping", "sleep"),
+					huh.NewOption("Boating", "boat"),
+					huh.NewOption("Gaming", "game"),
+				).
+				Value(&actualMultiSelect),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelectLabel("Have you slept well?", "Terribly!").
+		AddMultiSelectLabels("What are your favourite activities?", []string{"Gaming", "Sleeping"})
+
+	formInput, formOutput, closeResponder := responder.Start(t, defaultTimeout)
+
+	defer closeResponder()
+
+	// Act
+	err := myForm.WithInput(formInput).WithOutput(formOutput).Run()
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "terrible", actualSelect)
+	assert.Equal(t, []string{"sleep", "game"}, actualMultiSelect)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_SelectsOptionOfScrollingSelect(t *testing.T) {
+	t.Parallel()
+
+	var actualSelect int
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[int]().
+				Title("Which floor?").
+				Options(huh.NewOptions(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)...).
+				Height(5).
+				Value(&actualSelect),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelect("Which floor?", 7)
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, 7, actualSelect)
+}
+
+func TestHuhTest_SelectsOptionOfScrolledSelect(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		option int
+	}{
+		"option above the shown options": {option: 1},
+		"option among the shown options": {option: 8},
+		"option below the shown options": {option: 9},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			actualSelect := 7
+
+			myForm := huh.NewForm(
+				huh.NewGroup(
+					huh.NewSelect[int]().
+						Title("Which floor?").
+						Options(huh.NewOptions(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)...).
+						Height(4).
+						Value(&actualSelect),
+				),
+			)
+
+			responder := NewResponder().
+				AddSelect("Which floor?", testData.option)
+
+			// Act
+			err := RunForm(t, myForm, responder)
+
+			// Assert
+			require.NoError(t, err)
+
+			assert.Equal(t, testData.option, actualSelect)
+		})
+	}
+}
+
+func TestHuhTest_RespondsWithCustomKeyMap(t *testing.T) {
+	t.Parallel()
+
+	type answers struct {
+		input       string
+		confirm     bool
+		select_     string
+		multiSelect []string
+	}
+
+	var actual answers
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Input.Next = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Input.Submit = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Confirm.Toggle = key.NewBinding(key.WithKeys("t"))
+	keyMap.Select.Down = key.NewBinding(key.WithKeys("j"))
+	keyMap.MultiSelect.Down = key.NewBinding(key.WithKeys("j"))
+	keyMap.MultiSelect.Toggle = key.NewBinding(key.WithKeys("x"))
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("How Are You Feeling?").
+				Value(&actual.input),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Would you like a drink?").
+				Value(&actual.confirm),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Make a choice").
+				Options(huh.NewOptions("a", "b", "c")...).
+				Value(&actual.select_),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Please pick all options that apply").
+				Options(huh.NewOptions("a", "b", "c")...).
+				Value(&actual.multiSelect),
+		),
+	).WithKeyMap(keyMap)
+
+	formInput, formOutput, closeResponder := NewResponder().
+		WithKeyMap(keyMap).
+		AddResponse("How Are You Feeling?", "Amazing Thanks!").
+		AddConfirm("Would you like a drink?", ConfirmAffirm).
+		GuessConfirms().
+		AddSelect("Make a choice", 2).
+		AddMultiSelect("Please pick all options that apply", []int{0, 2}).
+		Start(t, defaultTimeout)
+
+	defer closeResponder()
+
+	// Act
+	err := myForm.WithInput(formInput).WithOutput(formOutput).Run()
+
+	// Assert
+	require.NoError(t, err)
+
+	expected := answers{
+		input:       "Amazing Thanks!",
+		confirm:     true,
+		select_:     "c",
+		multiSelect: []string{"a", "c"},
+	}
+
+	assert.Equal(t, expected, actual)
+}
+
+func TestHuhTest_RespondsToTextFields(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualStory   string
+		actualAddress string
+		actualName    string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Tell me a story").
+				Value(&actualStory),
+			huh.NewText().
+				Title("What's your address?").
+				Value(&actualAddress),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+		),
+	)
+
+	formInput, formOutput, closeResponder := NewResponder().
+		AddText("Tell me a story", "Once upon a time\nthere was a gopher\n\nThe end").
+		AddText("What's your address?", "Main Street 1").
+		AddResponse("What's your name?", "Gopher").
+		Start(t, defaultTimeout)
+
+	defer closeResponder()
+
+	// Act
+	err := myForm.WithInput(formInput).WithOutput(formOutput).Run()
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Once upon a time\nthere was a gopher\n\nThe end", actualStory)
+	assert.Equal(t, "Main Street 1", actualAddress)
+	assert.Equal(t, "Gopher", actualName)
+}
+
+func TestHuhTest_RespondsToTextFieldsWithCustomKeyMap(t *testing.T) {
+	t.Parallel()
+
+	var actualStory string
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Text.NewLine = key.NewBinding(key.WithKeys("alt+enter"))
+	keyMap.Text.Next = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Text.Submit = key.NewBinding(key.WithKeys("tab"))
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewText().
+				Title("Tell me a story").
+				Value(&actualStory),
+		),
+	).WithKeyMap(keyMap)
+
+	formInput, formOutput, closeResponder := NewResponder().
+		WithKeyMap(keyMap).
+		AddText("Tell me a story", "Once upon a time\nThe end").
+		Start(t, defaultTimeout)
+
+	defer closeResponder()
+
+	// Act
+	err := myForm.WithInput(formInput).WithOutput(formOutput).Run()
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Once upon a time\nThe end", actualStory)
+}
+
+func TestHuhTest_PicksFilesInFilePickers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualGuide string
+		actualNotes string
+		actualName  string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Which guide?").
+				CurrentDirectory("testdata/files").
+				Value(&actualGuide),
+		),
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Which notes?").
+				CurrentDirectory("testdata/files").
+				Value(&actualNotes),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+		),
+	)
+
+	formInput, formOutput, closeResponder := NewResponder().
+		WithFileSystem(os.DirFS("testdata/files")).
+		AddFilePick("Which guide?", "docs/guides/setup.md").
+		AddFilePick("Which notes?", "zebra.txt").
+		AddResponse("What's your name?", "Gopher").
+		Start(t, defaultTimeout)
+
+	defer closeResponder()
+
+	// Act
+	err := myForm.WithInput(formInput).WithOutput(formOutput).Run()
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join("testdata", "files", "docs", "guides", "setup.md"), actualGuide)
+	assert.Equal(t, filepath.Join("testdata", "files", "zebra.txt"), actualNotes)
+	assert.Equal(t, "Gopher", actualName)
+}
+
+func TestHuhTest_PicksFilesInTemporaryDirectory(t *testing.T) {
+	t.Parallel()
+
+	directory := t.TempDir()
+
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "a", "b"), 0o755))
+	require.NoError(t, os.MkdirAll(filepath.Join(directory, "c"), 0o755))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "a", "b", "file.txt"), []byte("b"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "a", "file.txt"), []byte("a"), 0o600))
+	require.NoError(t, os.WriteFile(filepath.Join(directory, "c", "file.txt"), []byte("c"), 0o600))
+
+	var actual string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Pick a file").
+				CurrentDirectory(directory).
+				Value(&actual),
+		),
+	)
+
+	responder := NewResponder().
+		WithFileSystem(os.DirFS(directory)).
+		AddFilePick("Pick a file", "a/b/file.txt")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join(directory, "a", "b", "file.txt"), actual)
+}
+
+func TestHuhTest_PicksFilesInFilePickerThatStartsInSubDirectory(t *testing.T) {
+	t.Parallel()
+
+	var actual string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Which guide?").
+				CurrentDirectory("testdata/files/docs").
+				Value(&actual),
+		),
+	)
+
+	responder := NewResponder().
+		WithFileSystem(os.DirFS("testdata/files")).
+		AddFilePick("Which guide?", "docs/guides/setup.md")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, filepath.Join("testdata", "files", "docs", "guides", "setup.md"), actual)
+}
+
+func TestHuhTest_FailsOnFilePickerInOtherDirectory(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Pick a file").
+				CurrentDirectory("testdata"),
+		),
+	)
+
+	responder := NewResponder().
+		WithFileSystem(os.DirFS("testdata/files")).
+		AddFilePick("Pick a file", "notes.txt")
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(time.Minute))
+
+	// Assert
+	require.Error(t, err)
+
+	// The test fails once the picker shows its directory, however long it takes to read it
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+	assert.Contains(t, responder.Transcript(), "file picker shows a directory that isn't along the path")
+	assert.NotContains(t, responder.Transcript(), "Deadline reached")
+}
+
+func TestHuhTest_FailsOnFileThatIsNotShown(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewFilePicker().
+				Title("Pick a file").
+				CurrentDirectory("testdata/files"),
+		),
+	)
+
+	responder := NewResponder().
+		WithFileSystem(os.DirFS("testdata/files")).
+		AddFilePick("Pick a file", ".hidden")
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(200*time.Millisecond))
+
+	// Assert
+	require.Error(t, err)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_StrictFailsOnUnansweredQuestion(t *testing.T) {
+	t.Parallel()
+
+	var first, second string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("How Are You Feeling?").
+				Value(&first),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&second),
+		),
+	)
+
+	dummyT := new(testingi.RuntimeT)
+
+	responder := NewResponder().
+		AddResponse("How Are You Feeling?", "Amazing Thanks!").
+		Strict()
+
+	start := time.Now()
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(defaultTimeout))
+
+	// Assert
+	require.Error(t, err)
+
+	assert.Less(t, time.Since(start), defaultTimeout)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+	assert.Equal(t, "Amazing Thanks!", first)
+}
+
+func TestHuhTest_StrictStopsFormRunWithoutContext(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?"),
+		),
+	)
+
+	dummyT := new(testingi.RuntimeT)
+
+	formInput, formOutput, closeResponder := NewResponder().
+		Strict().
+		Start(dummyT, time.Minute)
+
+	defer closeResponder()
+
+	result := make(chan error, 1)
+
+	// Act
+	go func() {
+		result <- myForm.WithInput(formInput).WithOutput(formOutput).Run()
+	}()
+
+	// Assert
+	select {
+	case err := <-result:
+		require.Error(t, err)
+	case <-time.After(10 * time.Second):
+		require.FailNow(t, "Form kept running after the responder stopped")
+	}
+
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_RespondsToNotes(t *testing.T) {
+	t.Parallel()
+
+	var actualName string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Welcome aboard").
+				Description("We'll ask you a few questions").
+				Next(true),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Thanks").
+				Description("Press enter to finish"),
+		),
+	)
+
+	responder := NewResponder().
+		AddNote("Welcome aboard").
+		RespondOnce().
+		AddResponse("What's your name?", "Gopher").
+		AddNote("Press enter to finish").
+		RespondOnce()
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_AutoAdvancesNotes(t *testing.T) {
+	t.Parallel()
+
+	var actualName string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Welcome aboard"),
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Before we start").
+				Description("This won't take long"),
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("About you"),
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+		),
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Thanks"),
+		),
+	)
+
+	responder := NewResponder().
+		AddNote("Before we start").
+		RespondOnce().
+		AddResponse("What's your name?", "Gopher").
+		AutoAdvanceNotes().
+		Strict()
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_StrictFailsOnUnansweredNote(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewNote().
+				Title("Welcome aboard"),
+		),
+	)
+
+	responder := NewResponder().
+		Strict()
+
+	dummyT := new(testingi.RuntimeT)
+
+	start := time.Now()
+
+	// Act
+	err := RunForm(dummyT, myForm, responder)
+
+	// Assert
+	require.Error(t, err)
+
+	assert.Less(t, time.Since(start), defaultTimeout)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_RespondsToValidationErrors(t *testing.T) {
+	t.Parallel()
+
+	var actualName, actualStory string
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName).
+				Validate(func(name string) error {
+					if len(name) < 3 {
+						return errors.New("name is too short")
+					}
+
+					if len(name) > 6 {
+						return errors.New("name is too long")
+					}
+
+					return nil
+				}),
+		),
+		huh.NewGroup(
+			huh.NewText().
+				Title("Tell me a story").
+				Value(&actualStory).
+				Validate(func(story string) error {
+					if strings.Count(story, "\n") < 1 {
+						return errors.New("story needs at least two lines")
+					}
+
+					return nil
+				}),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Go").
+		OnValidationError("too short", "Gopher the Great").
+		OnValidationError("too long", "Gopher").
+		AddText("Tell me a story", "The end").
+		OnValidationError("two lines", "Once upon a time\nThe end").
+		ExpectValidationError("What's your name?", "name is too short").
+		ExpectValidationError("What's your name?", "name is too long").
+		ExpectValidationError("Tell me a story", "at least two lines")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+	assert.Equal(t, "Once upon a time\nThe end", actualStory)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_FailsOnValidationErrorWithoutFollowUp(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Validate(func(string) error { return errors.New("name is taken") }),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher")
+
+	dummyT := new(testingi.RuntimeT)
+
+	start := time.Now()
+
+	// Act
+	err := RunForm(dummyT, myForm, responder)
+
+	// Assert
+	require.Error(t, err)
+
+	assert.Less(t, time.Since(start), defaultTimeout)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_ReplacesExistingValues(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualName       = "Old name"
+		actualAddress    = "Old street 1\nOld town\nOld country"
+		actualNickname   = "Goph"
+		actualNewsletter = true
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+			huh.NewText().
+				Title("What's your address?").
+				Value(&actualAddress),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your nickname?").
+				Value(&actualNickname),
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		ReplaceExisting().
+		AddText("What's your address?", "Main Street 1\nGo town").
+		ReplaceExisting().
+		AddAcceptDefault("What's your nickname?").
+		AddAcceptDefault("Do you want our newsletter?")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+	assert.Equal(t, "Main Street 1\nGo town", actualAddress)
+	assert.Equal(t, "Goph", actualNickname)
+	assert.True(t, actualNewsletter)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_AcceptsDefaultsWithCustomKeyMap(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualName       = "Gopher"
+		actualAddress    = "Main Street 1\nGo town"
+		actualColour     = "blue"
+		actualNewsletter = true
+	)
+
+	// None of the fields can be submitted with the default submit key
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Input.Next = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Input.Submit = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Text.Next = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Text.Submit = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Select.Next = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Select.Submit = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Confirm.Next = key.NewBinding(key.WithKeys("ctrl+o"))
+	keyMap.Confirm.Submit = key.NewBinding(key.WithKeys("ctrl+o"))
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+			huh.NewText().
+				Title("What's your address?").
+				Value(&actualAddress),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("What's your favourite colour?").
+				Options(huh.NewOptions("red", "blue", "green")...).
+				Value(&actualColour),
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+		),
+	).WithKeyMap(keyMap)
+
+	responder := NewResponder().
+		WithKeyMap(keyMap).
+		AddAcceptDefault("What's your name?").
+		AddAcceptDefault("What's your address?").
+		AddAcceptDefault("What's your favourite colour?").
+		AddAcceptDefault("Do you want our newsletter?")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+	assert.Equal(t, "Main Street 1\nGo town", actualAddress)
+	assert.Equal(t, "blue", actualColour)
+	assert.True(t, actualNewsletter)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_ReplacesExistingValuesWithTheSameValue(t *testing.T) {
+	t.Parallel()
+
+	// Typing the same value again shows the field as it was before it was answered, which shouldn't be answered again
+	var (
+		actualName = "Gopher"
+		actualCity string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+			huh.NewInput().
+				Title("Where do you live?").
+				Value(&actualCity),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		ReplaceExisting().
+		AddResponse("Where do you live?", "Go town")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", actualName)
+	assert.Equal(t, "Go town", actualCity)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_RespondsToConfirmsByReadingButtons(t *testing.T) {
+	t.Parallel()
+
+	// The focused button only stands out by its colours, which aren't rendered in tests by default
+	renderer := lipgloss.NewRenderer(io.Discard)
+	renderer.SetColorProfile(termenv.ANSI256)
+
+	theme := huh.ThemeCharm()
+	theme.Focused.FocusedButton = theme.Focused.FocusedButton.Renderer(renderer)
+	theme.Focused.BlurredButton = theme.Focused.BlurredButton.Renderer(renderer)
+
+	var (
+		actualNewsletter = true
+		actualTerms      = true
+		actualCookies    bool
+		actualInvoice    bool
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+			huh.NewConfirm().
+				Title("Do you accept the terms?").
+				Affirmative("Sure").
+				Negative("Nope").
+				Value(&actualTerms),
+			huh.NewConfirm().
+				Title("Do you accept cookies?").
+				Inline(true).
+				Value(&actualCookies),
+			huh.NewConfirm().
+				Title("Do you want an invoice?").
+				Affirmative("Please").
+				Negative("No thanks").
+				Inline(true).
+				Value(&actualInvoice),
+		),
+	).WithTheme(theme)
+
+	responder := NewResponder().
+		AddConfirm("newsletter?", ConfirmNegative).
+		AddConfirm("terms?", ConfirmAffirm).
+		AddConfirm("cookies?", ConfirmAffirm).
+		AddConfirm("invoice?", ConfirmNegative)
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.False(t, actualNewsletter)
+	assert.True(t, actualTerms)
+	assert.True(t, actualCookies)
+	assert.False(t, actualInvoice)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_RespondsToConfirmsUsingColorProfile(t *testing.T) {
+	// Not parallel, as the colour profile is shared by every test. Parallel tests only start once this one is done.
+	profile := lipgloss.ColorProfile()
+	lipgloss.SetColorProfile(termenv.ANSI256)
+
+	defer lipgloss.SetColorProfile(profile)
+
+	var (
+		actualNewsletter = true
+		actualTerms      = true
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+			huh.NewConfirm().
+				Title("Do you accept the terms?").
+				Value(&actualTerms),
+		),
+	)
+
+	responder := NewResponder().
+		AddConfirm("newsletter?", ConfirmNegative).
+		AddConfirm("terms?", ConfirmAffirm).
+		Strict()
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.False(t, actualNewsletter)
+	assert.True(t, actualTerms)
+
+	assert.NotContains(t, responder.Transcript(), "Warning")
+}
+
+func TestHuhTest_FailsOnConfirmsWithoutColours(t *testing.T) {
+	t.Parallel()
+
+	actualNewsletter := true
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+		),
+	)
+
+	responder := NewResponder().
+		AddConfirm("newsletter?", ConfirmAffirm)
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(time.Minute))
+
+	// Assert
+	require.Error(t, err)
+
+	// The test fails because of the confirm, long before the deadline is reached
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+	assert.Contains(t, responder.Transcript(), "\"Do you want our newsletter?\": the focused button can't be read")
+	assert.NotContains(t, responder.Transcript(), "Deadline reached")
+	assert.True(t, actualNewsletter, "Confirm should not have been answered")
+}
+
+func TestHuhTest_WarnsAboutGuessedConfirms(t *testing.T) {
+	t.Parallel()
+
+	var actualNewsletter bool
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Do you want our newsletter?").
+				Value(&actualNewsletter),
+		),
+	)
+
+	responder := NewResponder().
+		AddConfirm("newsletter?", ConfirmAffirm).
+		GuessConfirms()
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.True(t, actualNewsletter)
+	assert.Contains(t, responder.Transcript(), "Warning: \"Do you want our newsletter?\": the focused button can't be read")
+}
+
+func TestHuhTest_SelectsOptionsFromBoundValues(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualSelect           = "ok"
+		actualMultiSelect      = []string{"bike", "boat"}
+		actualMultiSelectLabel = []string{"sleep"}
+	)
+
+	activities := []huh.Option[string]{
+		huh.NewOption("Cycling", "bike"),
+		huh.NewOption("Sleeping", "sleep"),
+		huh.NewOption("Boating", "boat"),
+		huh.NewOption("Gaming", "game"),
+	}
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Have you slept well?").
+				Options(
+					huh.NewOption("Well!", "well"),
+					huh.NewOption("Terribly!", "terrible"),
+					huh.NewOption("It was OK!", "ok"),
+				).
+				Value(&actualSelect),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("What are your favourite activities?").
+				Options(activities...).
+				Value(&actualMultiSelect),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("What did you do yesterday?").
+				Options(activities...).
+				Value(&actualMultiSelectLabel),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelect("Have you slept well?", 1).
+		AddMultiSelect("What are your favourite activities?", []int{2, 3}).
+		AddMultiSelectLabels("What did you do yesterday?", []string{"Cycling"})
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "terrible", actualSelect)
+	assert.Equal(t, []string{"boat", "game"}, actualMultiSelect)
+	assert.Equal(t, []string{"bike"}, actualMultiSelectLabel)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_SelectsFilteredOptions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualRegion       string
+		actualRepositories []string
+	)
+
+	regions := []string{
+		"af-south-1", "ap-east-1", "ap-northeast-1", "ap-northeast-2", "ap-south-1", "ap-southeast-1",
+		"ca-central-1", "eu-central-1", "eu-north-1", "eu-south-1", "eu-west-1", "eu-west-2", "eu-west-3",
+		"me-south-1", "sa-east-1", "us-east-1", "us-east-2", "us-west-1", "us-west-2",
+	}
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which region?").
+				Options(huh.NewOptions(regions...)...).
+				Height(6).
+				Value(&actualRegion),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which repositories?").
+				Options(huh.NewOptions("huh", "huhtest", "bubbles", "bubbletea", "lipgloss", "glow")...).
+				Value(&actualRepositories),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelectFiltered("Which region?", "eu-west-2").
+		AddMultiSelectFiltered("Which repositories?", []string{"huh", "gloss", "tea"})
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "eu-west-2", actualRegion)
+	assert.Equal(t, []string{"huh", "bubbletea", "lipgloss"}, actualRepositories)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_PicksHiddenMultiSelectOptionsUsingFilters(t *testing.T) {
+	t.Parallel()
+
+	var actualFloors []int
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Which floors?").
+				Options(huh.NewOptions(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)...).
+				Height(5).
+				Value(&actualFloors),
+		),
+	)
+
+	responder := NewResponder().
+		AddMultiSelectFiltered("Which floors?", []string{"1", "8"})
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, []int{1, 8}, actualFloors)
+}
+
+func TestHuhTest_FailsOnHiddenMultiSelectIndex(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[int]().
+				Title("Which floors?").
+				Options(huh.NewOptions(0, 1, 2, 3, 4, 5, 6, 7, 8, 9)...).
+				Height(5),
+		),
+	)
+
+	responder := NewResponder().
+		AddMultiSelect("Which floors?", []int{1, 8})
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(time.Minute))
+
+	// Assert
+	require.Error(t, err)
+
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+	assert.Contains(t, responder.Transcript(), "8: option out of range")
+	assert.NotContains(t, responder.Transcript(), "Deadline reached")
+}
+
+func TestHuhTest_FailsOnAmbiguousFilter(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which repository?").
+				Options(huh.NewOptions("huh", "huhtest", "bubbles", "bubbletea")...),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelectFiltered("Which repository?", "bubb")
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(200*time.Millisecond))
+
+	// Assert
+	require.Error(t, err)
+
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_SelectsOptionsOfInlineSelects(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualSize  = "large"
+		actualColor string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which size?").
+				Options(huh.NewOptions("small", "medium", "large")...).
+				Inline(true).
+				Value(&actualSize),
+			huh.NewSelect[string]().
+				Title("Which color?").
+				Options(huh.NewOptions("red", "green", "blue")...).
+				Inline(true).
+				Value(&actualColor),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelect("Which size?", 1).
+		AddSelect("Which color?", 2)
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "medium", actualSize)
+	assert.Equal(t, "blue", actualColor)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_FailsOnLabelOfInlineSelect(t *testing.T) {
+	t.Parallel()
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which size?").
+				Options(huh.NewOptions("small", "medium", "large")...).
+				Inline(true),
+		),
+	)
+
+	responder := NewResponder().
+		AddSelectLabel("Which size?", "medium")
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, myForm, responder, WithTimeout(200*time.Millisecond))
+
+	// Assert
+	require.Error(t, err)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestHuhTest_SelectsAllOrNoOptions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualToppings = []string{"cheese"}
+		actualSauces   = []string{"ketchup", "mayo"}
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which toppings?").
+				Options(huh.NewOptions("lettuce", "cheese", "tomato")...).
+				Limit(3).
+				Value(&actualToppings),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which sauces?").
+				Options(huh.NewOptions("ketchup", "mustard", "mayo")...).
+				Value(&actualSauces),
+		),
+	)
+
+	responder := NewResponder().
+		AddMultiSelectAll("Which toppings?").
+		AddMultiSelectNone("Which sauces?")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"lettuce", "cheese", "tomato"}, actualToppings)
+	assert.Empty(t, actualSauces)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_FailsOnSelectionLimit(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		responder *Responder
+	}{
+		"indexes": {
+			responder: NewResponder().AddMultiSelect("Which toppings?", []int{0, 1, 2}),
+		},
+		"all": {
+			responder: NewResponder().AddMultiSelectAll("Which toppings?"),
+		},
+		"out of range": {
+			responder: NewResponder().AddMultiSelect("Which toppings?", []int{3}),
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			myForm := huh.NewForm(
+				huh.NewGroup(
+					huh.NewMultiSelect[string]().
+						Title("Which toppings?").
+						Options(huh.NewOptions("lettuce", "cheese", "tomato")...).
+						Limit(2),
+				),
+			)
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			err := RunForm(dummyT, myForm, testData.responder, WithTimeout(200*time.Millisecond))
+
+			// Assert
+			require.Error(t, err)
+
+			assert.True(t, dummyT.Failed(), "Test should have failed")
+		})
+	}
+}
+
+func TestHuhTest_AcceptsSuggestions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualRepository string
+		actualLanguage   string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Which repository?").
+				Suggestions([]string{"huh", "huhtest", "bubbletea"}).
+				Value(&actualRepository),
+			huh.NewInput().
+				Title("Which language?").
+				Suggestions([]string{"Go", "Rust"}).
+				Value(&actualLanguage),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponseWithSuggestion("Which repository?", "bub", "bubbletea").
+		AddResponseWithSuggestion("Which language?", "R", "Rust")
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "bubbletea", actualRepository)
+	assert.Equal(t, "Rust", actualLanguage)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_FailsOnUnexpectedSuggestion(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		prefix     string
+		completion string
+	}{
+		"other suggestion": {prefix: "huh", completion: "huhtest"},
+		"no suggestion":    {prefix: "glow", completion: "glow-cli"},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			myForm := huh.NewForm(
+				huh.NewGroup(
+					huh.NewInput().
+						Title("Which repository?").
+						Suggestions([]string{"huh", "huhtest", "bubbletea"}),
+				),
+			)
+
+			responder := NewResponder().
+				AddResponseWithSuggestion("Which repository?", testData.prefix, testData.completion)
+
+			dummyT := new(testingi.RuntimeT)
+
+			// Act
+			err := RunForm(dummyT, myForm, responder, WithTimeout(200*time.Millisecond))
+
+			// Assert
+			require.Error(t, err)
+
+			assert.True(t, dummyT.Failed(), "Test should have failed")
+		})
+	}
+}
+
+func TestHuhTest_SendsKeys(t *testing.T) {
+	t.Parallel()
+
+	var (
+		actualName         string
+		actualRepositories []string
+		actualLanguage     string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+		),
+		huh.NewGroup(
+			huh.NewMultiSelect[string]().
+				Title("Which repositories?").
+				Options(huh.NewOptions("huh", "bubbles", "bubbletea")...).
+				Value(&actualRepositories),
+		),
+		huh.NewGroup(
+			huh.NewSelect[string]().
+				Title("Which language?").
+				Options(huh.NewOptions("Go", "Rust", "Zig")...).
+				Value(&actualLanguage),
+		),
+	)
+
+	responder := NewResponder().
+		AddKeys("What's your name?", Keys("Jo", Keys("nh", KeyBackspace, KeyBackspace), "hn", KeyEnter)).
+		AddKeys("Which repositories?", Keys("/", Paste("bubble"), KeyEsc, KeySpace, KeyDown, KeySpace, KeyEnter)).
+		AddKeys("Which language?", Keys("jj", "k", KeyEnter))
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "John", actualName)
+	assert.Equal(t, []string{"bubbles", "bubbletea"}, actualRepositories)
+	assert.Equal(t, "Rust", actualLanguage)
+
+	responder.AssertExpectations(t)
+}
+
+// lockedValue is a huh.Accessor that can be read in the functions given to TitleFunc and OptionsFunc, which huh calls
+// in separate goroutines while the form writes to the value.
+type lockedValue[T any] struct {
+	lock  sync.Mutex
+	value T
+
+	// binding is given to TitleFunc and OptionsFunc to notice changes, it's only used by the form itself
+	binding T
+}
+
+func (v *lockedValue[T]) Get() T {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	return v.value
+}
+
+func (v *lockedValue[T]) Set(value T) {
+	v.lock.Lock()
+	v.value = value
+	v.lock.Unlock()
+
+	v.binding = value
+}
+
+func TestHuhTest_GoesBackToPreviousQuestion(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var (
+		actualName     lockedValue[string]
+		actualAge      string
+		actualNickname string
+		actualCity     string
+		actualConfirm  bool
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Accessor(&actualName),
+			huh.NewInput().
+				Title("How old are you?").
+				Value(&actualAge),
+			huh.NewSelect[string]().
+				Title("Where do you live?").
+				OptionsFunc(func() []huh.Option[string] {
+					if actualName.Get() == "Jon" {
+						return huh.NewOptions("Winterfell", "Castle Black")
+					}
+
+					return huh.NewOptions("Amsterdam", "Utrecht")
+				}, &actualName.binding).
+				Value(&actualCity),
+			huh.NewInput().
+				TitleFunc(func() string { return "What's the nickname of " + actualName.Get() + "?" }, &actualName.binding).
+				Value(&actualNickname),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Is this correct?").
+				Value(&actualConfirm),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Jon").
+		AddResponse("What's your name?", "John").ReplaceExisting().
+		AddResponse("How old are you?", "30").
+		AddResponse("How old are you?", "31").ReplaceExisting().
+		AddSelectLabel("Where do you live?", "Castle Black").
+		AddSelectLabel("Where do you live?", "Utrecht").
+		AddResponse("What's the nickname of Jon?", "Snow").
+		AddResponse("What's the nickname of John?", "Johnny").ReplaceExisting().
+		AddConfirm("Is this correct?", ConfirmAffirm).GoBackTo("What's your name?").
+		GuessConfirms()
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "John", actualName.Get())
+	assert.Equal(t, "31", actualAge)
+	assert.Equal(t, "Utrecht", actualCity)
+	assert.Equal(t, "Johnny", actualNickname)
+	assert.True(t, actualConfirm)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_GoesBackAmountOfFields(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var (
+		actualFirst  string
+		actualSecond string
+		actualThird  string
+	)
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("First?").
+				Value(&actualFirst),
+		),
+		huh.NewGroup(
+			huh.NewInput().
+				Title("Second?").
+				Value(&actualSecond),
+			huh.NewInput().
+				Title("Third?").
+				Value(&actualThird),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("First?", "a").
+		AddResponse("First?", "b").ReplaceExisting().
+		AddResponse("Second?", "c").ReplaceExisting().
+		AddResponse("Third?", "d").GoBack(2)
+
+	// Act
+	err := RunForm(t, myForm, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "b", actualFirst)
+	assert.Equal(t, "c", actualSecond)
+	assert.Equal(t, "d", actualThird)
+
+	responder.AssertExpectations(t)
+}
+
+func TestHuhTest_AbortsForm(t *testing.T) {
+	t.Parallel()
+
+	// Arrange
+	var (
+		actualName     string
+		actualLanguage string
+		actualConfirm  bool
+	)
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Quit = key.NewBinding(key.WithKeys("esc"))
+
+	myForm := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().
+				Title("What's your name?").
+				Value(&actualName),
+			huh.NewInput().
+				Title("Which language?").
+				Value(&actualLanguage),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().
+				Title("Is this correct?").
+				Value(&actualConfirm),
+		),
+	).WithKeyMap(keyMap)
+
+	responder := NewResponder().
+		WithKeyMap(keyMap).
+		AddResponse("What's your name?", "John").
+		AbortAt("Which language?").
+		AddConfirm("Is this correct?", ConfirmAffirm)
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	result := AssertFormAborted(t, myForm, responder)
+
+	// Assert
+	assert.True(t, result)
+
+	assert.Equal(t, "John", actualName)
+	assert.Empty(t, actualLanguage)
+	assert.False(t, actualConfirm)
+
+	responder.AssertExpectations(dummyT)
+	assert.True(t, dummyT.Failed(), "Confirm should not have been answered")
+}
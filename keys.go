@@ -0,0 +1,473 @@
+package huhtest
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+)
+
+// fieldType dictates which key bindings of a huh.KeyMap apply to a response.
+type fieldType string
+
+const (
+	fieldInput       fieldType = "input"
+	fieldText        fieldType = "text"
+	fieldSelect      fieldType = "select"
+	fieldMultiSelect fieldType = "multiselect"
+	fieldConfirm     fieldType = "confirm"
+	fieldFilePicker  fieldType = "filepicker"
+	fieldNote        fieldType = "note"
+
+	// fieldKeystrokes is used for responses that are sent exactly as they are, to any type of field
+	fieldKeystrokes fieldType = "keystrokes"
+
+	// fieldAbort is used for responses that quit the form, at any type of field
+	fieldAbort fieldType = "abort"
+
+	// fieldAny is used for responses that work for any type of field, it's replaced by the type of the field that's
+	// focused once it's answered, see focusedFieldType
+	fieldAny fieldType = "any"
+)
+
+// keySeparator is an escape sequence that bubbletea does not recognise as a key, so huh ignores it. Bubbletea
+// reports consecutive characters as a single key press, so we send this after keys that are regular characters.
+const keySeparator = "\x1b[0X"
+
+// Key is one or more keystrokes that can be sent to a form using AddKeys, the constants below cover keys that can't
+// be typed. Any other text is sent as separate key presses of its characters, see Keys.
+type Key string
+
+// escapeKey is a lone escape character, which bubbletea only reads as esc if it's the last input that it reads at
+// once. The keySeparator after it marks where the input has to be split, see splitInput.
+const escapeKey = "\x1b" + keySeparator
+
+// Keys that can't be typed, combine them with text using Keys.
+const (
+	KeyEnter     Key = defaultSubmit
+	KeyTab       Key = "\x09"
+	KeyShiftTab  Key = "\x1b[Z"
+	KeyEsc       Key = escapeKey
+	KeyCtrlC     Key = "\x03"
+	KeyBackspace Key = backspace
+	KeyDelete    Key = "\x1b[3~"
+	KeySpace     Key = selectOption
+	KeyUp        Key = arrowUp
+	KeyDown      Key = arrowDown
+	KeyLeft      Key = arrowLeft
+	KeyRight     Key = arrowRight
+	KeyHome      Key = listStart
+	KeyEnd       Key = lineEnd
+	KeyPageUp    Key = "\x1b[5~"
+	KeyPageDown  Key = "\x1b[6~"
+)
+
+// Keys combines keys and text into a single Key for AddKeys, for example:
+//
+//	Keys("foo", KeyTab, KeyDown, KeyEnter)
+//
+// Every character of the text is sent as a separate key press, so that "jj" is read as pressing j twice. Use Paste
+// to send text as a whole. The result of Keys can be given to Keys again.
+func Keys(keys ...Key) Key {
+	var result strings.Builder
+
+	for _, key := range keys {
+		// Escape sequences, pastes and keys that were combined before are sent as they are
+		if strings.HasPrefix(string(key), "\x1b") {
+			result.WriteString(string(key))
+			continue
+		}
+
+		for _, character := range key {
+			result.WriteString(separateKey(string(character)))
+		}
+	}
+
+	if result.Len() == 0 {
+		return ""
+	}
+
+	// The keySeparator in front marks the keys as combined, so that they aren't split up again if they're nested
+	return Key(keySeparator + result.String())
+}
+
+// Paste returns the text as a bracketed paste, which a form reads as text that's typed at once. Unlike text that's
+// given to Keys, pasted text never matches a key binding.
+func Paste(text string) Key {
+	return Key(pasteStart + text + pasteEnd)
+}
+
+// splitInput splits input after every escapeKey without its keySeparator, so that every lone escape character ends
+// up at the end of a separate write to the form.
+func splitInput(input string) []string {
+	var result []string
+
+	for {
+		before, after, found := strings.Cut(input, escapeKey)
+		if !found {
+			break
+		}
+
+		result = append(result, before+"\x1b")
+		input = after
+	}
+
+	if input != "" || len(result) == 0 {
+		result = append(result, input)
+	}
+
+	return result
+}
+
+// keySequences maps the names of keys as used in key.Binding to the input a terminal sends for them.
+// Keys that consist of a single character are not listed, as they represent themselves.
+var keySequences = map[string]string{
+	"enter":     "\x0D",
+	"tab":       string(KeyTab),
+	"shift+tab": string(KeyShiftTab),
+	"esc":       escapeKey,
+	"backspace": "\x7f",
+	"delete":    string(KeyDelete),
+	"up":        arrowUp,
+	"down":      arrowDown,
+	"right":     arrowRight,
+	"left":      string(KeyLeft),
+	"home":      listStart,
+	"end":       lineEnd,
+	"pgup":      string(KeyPageUp),
+	"pgdown":    string(KeyPageDown),
+	"space":     " ",
+}
+
+// keySequence returns the input for the given key name, including keys with ctrl+ and alt+ modifiers.
+func keySequence(name string) (string, bool) {
+	if sequence, ok := keySequences[name]; ok {
+		return sequence, true
+	}
+
+	if letter, ok := strings.CutPrefix(name, "ctrl+"); ok {
+		if len(letter) != 1 || letter[0] < 'a' || letter[0] > 'z' {
+			return "", false
+		}
+
+		return string(rune(letter[0] - 'a' + 1)), true
+	}
+
+	if rest, ok := strings.CutPrefix(name, "alt+"); ok {
+		sequence, ok := keySequence(rest)
+		if !ok {
+			return "", false
+		}
+
+		return "\x1b" + sequence, true
+	}
+
+	if len([]rune(name)) == 1 {
+		return name, true
+	}
+
+	return "", false
+}
+
+// separateKey appends the keySeparator to the input of a key if it's a regular character.
+func separateKey(sequence string) string {
+	character, size := utf8.DecodeRuneInString(sequence)
+	if size != len(sequence) || character <= 0x1f || character == 0x7f || character == ' ' {
+		return sequence
+	}
+
+	return sequence + keySeparator
+}
+
+const (
+	// pasteStart and pasteEnd surround text that's pasted in a terminal with bracketed paste enabled
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+// typeText sends the regular characters in the text as bracketed pastes, control characters in between are
+// sent as they are. Bubbletea reports consecutive characters as a single key press, so words like "end" or "up"
+// would otherwise match key bindings, pasted text never does.
+func typeText(text string) string {
+	var result, pasted strings.Builder
+
+	paste := func() {
+		if pasted.Len() > 0 {
+			result.WriteString(pasteStart + pasted.String() + pasteEnd)
+			pasted.Reset()
+		}
+	}
+
+	for _, character := range text {
+		if character <= 0x1f || character == 0x7f {
+			paste()
+			result.WriteRune(character)
+
+			continue
+		}
+
+		pasted.WriteRune(character)
+	}
+
+	paste()
+
+	return result.String()
+}
+
+// textMaxLines is the amount of lines that a text field holds at most, huh doesn't change the default of bubbles
+const textMaxLines = 99
+
+// clearExisting returns the input that deletes the whole value of an input or text field, wherever the cursor is.
+// Fields that answers aren't typed into are left alone. Like clearText, a text field is cleared line by line.
+func clearExisting(field fieldType) string {
+	switch field {
+	case fieldInput:
+		return lineEnd + clearLine
+	case fieldText:
+		return inputEnd + strings.Repeat(clearLine+backspace, textMaxLines-1) + clearLine
+	default:
+		return ""
+	}
+}
+
+// errNotTyped is returned by clearText for fields that answers aren't typed into
+var errNotTyped = errors.New("answers can only be typed into inputs and text fields")
+
+// clearText returns the input that deletes the text that was typed into an input or text field, which is done line
+// by line as a text field only clears the line the cursor is on. These keys are bound by the bubbles components that
+// huh uses, so they can't be changed in a huh.KeyMap.
+func clearText(field fieldType, typed string) (string, error) {
+	switch field {
+	case fieldInput:
+		return clearLine, nil
+	case fieldText:
+		return strings.Repeat(clearLine+backspace, strings.Count(typed, newLine)) + clearLine, nil
+	default:
+		return "", fmt.Errorf("%s: %w", field, errNotTyped)
+	}
+}
+
+// errNoUsableKey is returned if none of the keys in a binding can be sent by the Responder
+var errNoUsableKey = errors.New("no usable key in binding")
+
+// bindingSequence returns the input of the first key in the binding that we know how to send. If preferred
+// bindings are given, keys that are also in those bindings are chosen first.
+func bindingSequence(binding key.Binding, preferred ...key.Binding) (string, error) {
+	keys := binding.Keys()
+
+	for _, other := range preferred {
+		for _, name := range keys {
+			if !slices.Contains(other.Keys(), name) {
+				continue
+			}
+
+			if sequence, ok := keySequence(name); ok {
+				return sequence, nil
+			}
+		}
+	}
+
+	for _, name := range keys {
+		if sequence, ok := keySequence(name); ok {
+			return sequence, nil
+		}
+	}
+
+	return "", fmt.Errorf("%q: %w", keys, errNoUsableKey)
+}
+
+// withoutKeys returns a copy of the binding without the keys that are also bound in the others.
+func withoutKeys(binding key.Binding, others ...key.Binding) key.Binding {
+	keys := slices.DeleteFunc(slices.Clone(binding.Keys()), func(name string) bool {
+		return slices.ContainsFunc(others, func(other key.Binding) bool { return slices.Contains(other.Keys(), name) })
+	})
+
+	return key.NewBinding(key.WithKeys(keys...))
+}
+
+// fieldKeys contains the keystrokes used to answer a specific type of field
+type fieldKeys struct {
+	// replacer swaps the default keystrokes in an answer with the bound ones
+	replacer *strings.Replacer
+
+	// submit moves on to the next field, or submits the form
+	submit string
+
+	// previous moves back to the previous field
+	previous string
+}
+
+// keyBindings translates the default keystrokes in answers to the ones bound in a huh.KeyMap. A nil
+// keyBindings leaves answers alone.
+type keyBindings map[fieldType]fieldKeys
+
+// newKeyBindings derives the keystrokes for every type of field from the given huh.KeyMap. Since fields
+// only enable their Next binding if they're not the last field and Submit if they are, we prefer
+// keys that are bound to both to move on.
+func newKeyBindings(keyMap *huh.KeyMap) (keyBindings, error) {
+	var errs []error
+
+	sequence := func(binding key.Binding, preferred ...key.Binding) string {
+		result, err := bindingSequence(binding, preferred...)
+		errs = append(errs, err)
+
+		return separateKey(result)
+	}
+
+	result := keyBindings{
+		fieldInput: {
+			// An input that's answered with a suggestion is submitted using defaultSubmit, see suggestionPick
+			replacer: strings.NewReplacer(
+				acceptSuggestion, sequence(keyMap.Input.AcceptSuggestion),
+				defaultSubmit, sequence(keyMap.Input.Next, keyMap.Input.Submit),
+			),
+			submit:   sequence(keyMap.Input.Next, keyMap.Input.Submit),
+			previous: sequence(keyMap.Input.Prev),
+		},
+		fieldText: {
+			replacer: strings.NewReplacer(
+				newLine, sequence(keyMap.Text.NewLine),
+			),
+			submit:   sequence(keyMap.Text.Next, keyMap.Text.Submit),
+			previous: sequence(keyMap.Text.Prev),
+		},
+		fieldSelect: {
+			// A filtered select is answered in steps, which submit using defaultSubmit, see filterPick. An inline
+			// select is navigated using arrowRight, see resolveSelectIndex.
+			replacer: strings.NewReplacer(
+				arrowDown, sequence(keyMap.Select.Down),
+				arrowUp, sequence(keyMap.Select.Up),
+				arrowRight, sequence(keyMap.Select.Right),
+				listStart, sequence(keyMap.Select.GotoTop),
+				filterOptions, sequence(keyMap.Select.Filter),
+				defaultSubmit, sequence(keyMap.Select.Next, keyMap.Select.Submit),
+			),
+			submit:   sequence(keyMap.Select.Next, keyMap.Select.Submit),
+			previous: sequence(keyMap.Select.Prev),
+		},
+		fieldMultiSelect: {
+			// The steps of a filtered multi-select use defaultSubmit like enter is used in huh, which keeps the
+			// filtered options while filtering and moves on otherwise. A key bound to both is preferred.
+			replacer: strings.NewReplacer(
+				arrowDown, sequence(keyMap.MultiSelect.Down),
+				arrowUp, sequence(keyMap.MultiSelect.Up),
+				selectOption, sequence(keyMap.MultiSelect.Toggle),
+				filterOptions, sequence(keyMap.MultiSelect.Filter),
+				defaultSubmit, sequence(keyMap.MultiSelect.SetFilter, keyMap.MultiSelect.Next),
+			),
+			submit:   sequence(keyMap.MultiSelect.Next, keyMap.MultiSelect.Submit),
+			previous: sequence(keyMap.MultiSelect.Prev),
+		},
+		fieldFilePicker: {
+			// Directories are opened using arrowRight and files are selected using defaultSubmit, both are bound
+			// to enter by default but opening a directory with a key that also selects could pick the directory.
+			// The picker is closed using escapeKey to read its directory again and arrowLeft goes back without
+			// closing it, see filePick.
+			replacer: strings.NewReplacer(
+				arrowDown, sequence(keyMap.FilePicker.Down),
+				arrowUp, sequence(keyMap.FilePicker.Up),
+				arrowRight, sequence(withoutKeys(keyMap.FilePicker.Open, keyMap.FilePicker.Select)),
+				arrowLeft, sequence(withoutKeys(keyMap.FilePicker.Back, keyMap.FilePicker.Close)),
+				escapeKey, sequence(keyMap.FilePicker.Close),
+				defaultSubmit, sequence(keyMap.FilePicker.Select),
+			),
+			previous: sequence(keyMap.FilePicker.Prev),
+		},
+		fieldNote: {
+			replacer: strings.NewReplacer(),
+			submit:   sequence(keyMap.Note.Next, keyMap.Note.Submit),
+			previous: sequence(keyMap.Note.Prev),
+		},
+		fieldConfirm: {
+			// Toggling moves the focus to the other button, see resolveConfirm
+			replacer: strings.NewReplacer(
+				arrowRight, sequence(keyMap.Confirm.Toggle),
+			),
+			submit:   sequence(keyMap.Confirm.Next, keyMap.Confirm.Submit),
+			previous: sequence(keyMap.Confirm.Prev),
+		},
+		fieldAbort: {
+			replacer: strings.NewReplacer(),
+			submit:   sequence(keyMap.Quit),
+		},
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// replace returns the input with the keystrokes bound for the response's field type, without submitting it. If no
+// keys are known for the field type, the input is returned as-is.
+func (k keyBindings) replace(res *response, input string) string {
+	keys, ok := k[res.field]
+	if !ok {
+		return input
+	}
+
+	return replaceKeys(keys.replacer, input)
+}
+
+// previous returns the input that moves back from a field of the response's field type to the previous field. If no
+// keys are known for the field type, the default key is returned.
+func (k keyBindings) previous(res *response) string {
+	keys, ok := k[res.field]
+	if !ok || keys.previous == "" {
+		return string(KeyShiftTab)
+	}
+
+	return keys.previous
+}
+
+// replaceKeys returns the input with its keystrokes swapped by the replacer, text that's pasted is left alone as
+// it doesn't contain keystrokes, see typeText.
+func replaceKeys(replacer *strings.Replacer, input string) string {
+	var result strings.Builder
+
+	for input != "" {
+		keys, rest, pasting := strings.Cut(input, pasteStart)
+		result.WriteString(replacer.Replace(keys))
+
+		if !pasting {
+			break
+		}
+
+		pasted, after, _ := strings.Cut(rest, pasteEnd)
+		result.WriteString(pasteStart + pasted + pasteEnd)
+
+		input = after
+	}
+
+	return result.String()
+}
+
+// translate returns the answer with the keystrokes bound for the response's field type, including the
+// key to submit it. If no keys are known for the field type, the answer is returned with its default submit.
+// Answers to fields that text is typed into are typed character by character, see typeText, after deleting the value
+// of the field if the response replaces it. Responses added using AddKeys are sent as they are.
+func (k keyBindings) translate(res *response, answer string) string {
+	if res.field == fieldKeystrokes {
+		return answer
+	}
+
+	if res.field == fieldInput || res.field == fieldText {
+		answer = typeText(answer)
+	}
+
+	if res.replaceExisting {
+		answer = clearExisting(res.field) + answer
+	}
+
+	keys, ok := k[res.field]
+	if !ok {
+		return answer + res.submitCharacter()
+	}
+
+	return replaceKeys(keys.replacer, answer) + keys.submit
+}
@@ -0,0 +1,578 @@
+package huhtest
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/huh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeySequence_ReturnsExpectedSequence(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"enter":     "\r",
+		"shift+tab": "\x1b[Z",
+		"down":      arrowDown,
+		"j":         "j",
+		" ":         " ",
+		"ö":         "ö",
+		"ctrl+c":    "\x03",
+		"ctrl+j":    "\n",
+		"alt+enter": "\x1b\r",
+		"alt+x":     "\x1bx",
+	}
+
+	for name, expected := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, ok := keySequence(name)
+
+			// Assert
+			require.True(t, ok)
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestKeySequence_ReturnsFalseOnUnknownKey(t *testing.T) {
+	t.Parallel()
+
+	tests := []string{"f13", "ctrl+1", "ctrl+enter", "alt+f13", "hello"}
+
+	for _, name := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, ok := keySequence(name)
+
+			// Assert
+			assert.False(t, ok)
+			assert.Empty(t, result)
+		})
+	}
+}
+
+func TestSeparateKey_SeparatesRegularCharacters(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"j":       "j" + keySeparator,
+		"ö":       "ö" + keySeparator,
+		" ":       " ",
+		"\r":      "\r",
+		"\x7f":    "\x7f",
+		arrowDown: arrowDown,
+		"\x1bx":   "\x1bx",
+	}
+
+	for sequence, expected := range tests {
+		t.Run(sequence, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := separateKey(sequence)
+
+			// Assert
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestBindingSequence_ReturnsExpectedSequence(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		binding   key.Binding
+		preferred []key.Binding
+
+		expected string
+	}{
+		"first key": {
+			binding:  key.NewBinding(key.WithKeys("down", "j")),
+			expected: arrowDown,
+		},
+		"first usable key": {
+			binding:  key.NewBinding(key.WithKeys("f13", "j")),
+			expected: "j",
+		},
+		"preferred key": {
+			binding:   key.NewBinding(key.WithKeys("tab", "enter")),
+			preferred: []key.Binding{key.NewBinding(key.WithKeys("enter"))},
+			expected:  "\r",
+		},
+		"no preferred key in binding": {
+			binding:   key.NewBinding(key.WithKeys("tab")),
+			preferred: []key.Binding{key.NewBinding(key.WithKeys("enter"))},
+			expected:  "\t",
+		},
+		"disabled binding": {
+			binding:  key.NewBinding(key.WithKeys("tab"), key.WithDisabled()),
+			expected: "\t",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := bindingSequence(testData.binding, testData.preferred...)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestBindingSequence_ReturnsErrorOnNoUsableKey(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	binding := key.NewBinding(key.WithKeys("f13"))
+
+	// Act
+	result, err := bindingSequence(binding)
+
+	// Assert
+	require.ErrorIs(t, err, errNoUsableKey)
+	assert.Empty(t, result)
+}
+
+func TestWithoutKeys_RemovesKeysOfOtherBindings(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	binding := key.NewBinding(key.WithKeys("l", "right", "enter"))
+	other := key.NewBinding(key.WithKeys("enter"))
+
+	// Act
+	result := withoutKeys(binding, other)
+
+	// Assert
+	assert.Equal(t, []string{"l", "right"}, result.Keys())
+	assert.Equal(t, []string{"l", "right", "enter"}, binding.Keys())
+}
+
+func TestClearText_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field fieldType
+		typed string
+
+		expected string
+	}{
+		"input": {
+			field:    fieldInput,
+			typed:    "hello",
+			expected: "<clear>",
+		},
+		"text": {
+			field:    fieldText,
+			typed:    "hello",
+			expected: "<clear>",
+		},
+		"multi-line text": {
+			field:    fieldText,
+			typed:    "a" + newLine + newLine + "b",
+			expected: "<clear><backspace><clear><backspace><clear>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, err := clearText(testData.field, testData.typed)
+
+			// Assert
+			require.NoError(t, err)
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestClearText_ReturnsErrorOnFieldsThatAreNotTyped(t *testing.T) {
+	t.Parallel()
+	// Act
+	result, err := clearText(fieldSelect, arrowDown)
+
+	// Assert
+	require.ErrorIs(t, err, errNotTyped)
+	assert.Empty(t, result)
+}
+
+func TestKeyBindings_Replace_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.FilePicker.Down = key.NewBinding(key.WithKeys("ctrl+n"))
+	keyMap.FilePicker.Select = key.NewBinding(key.WithKeys("enter", "l"))
+	keyMap.FilePicker.Close = key.NewBinding(key.WithKeys("q"))
+	keyMap.MultiSelect.Toggle = key.NewBinding(key.WithKeys("x"))
+	keyMap.MultiSelect.Filter = key.NewBinding(key.WithKeys("?"))
+	keyMap.MultiSelect.SetFilter = key.NewBinding(key.WithKeys("esc", "tab"))
+	keyMap.MultiSelect.Next = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Input.AcceptSuggestion = key.NewBinding(key.WithKeys("right"))
+
+	keys, err := newKeyBindings(keyMap)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		keys     keyBindings
+		response *response
+		input    string
+
+		expected string
+	}{
+		"default file picker": {
+			response: &response{field: fieldFilePicker},
+			input:    escapeKey + arrowRight + arrowLeft + arrowDown + defaultSubmit,
+			expected: "<esc><right><left><down><submit>",
+		},
+		"file picker": {
+			keys:     keys,
+			response: &response{field: fieldFilePicker},
+			input:    escapeKey + arrowRight + arrowLeft + arrowDown + defaultSubmit,
+			expected: "q<right>h\x0e<submit>",
+		},
+		"filtered multi select": {
+			keys:     keys,
+			response: &response{field: fieldMultiSelect},
+			input:    filterOptions + typeText("a b") + defaultSubmit + selectOption,
+			expected: "?a b<tab>x",
+		},
+		"input with suggestion": {
+			keys:     keys,
+			response: &response{field: fieldInput},
+			input:    typeText("hu") + acceptSuggestion + defaultSubmit,
+			expected: "hu<right><submit>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.keys.replace(testData.response, testData.input)
+
+			// Assert
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestTypeText_PastesRegularCharacters(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]string{
+		"":        "",
+		"end":     pasteStart + "end" + pasteEnd,
+		"the end": pasteStart + "the end" + pasteEnd,
+		"a\nb":    pasteStart + "a" + pasteEnd + "\n" + pasteStart + "b" + pasteEnd,
+		"\n\nü":   "\n\n" + pasteStart + "ü" + pasteEnd,
+		"tab\t":   pasteStart + "tab" + pasteEnd + "\t",
+	}
+
+	for text, expected := range tests {
+		t.Run(text, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := typeText(text)
+
+			// Assert
+			assert.Equal(t, expected, result)
+		})
+	}
+}
+
+func TestReplaceKeys_LeavesPastedTextAlone(t *testing.T) {
+	t.Parallel()
+
+	replacer := strings.NewReplacer(selectOption, "x", filterOptions, "f")
+
+	tests := map[string]struct {
+		input string
+
+		expected string
+	}{
+		"keys":              {input: " / ", expected: "xfx"},
+		"pasted text":       {input: typeText("a b/c"), expected: typeText("a b/c")},
+		"keys around paste": {input: "/" + typeText("a b") + " ", expected: "f" + typeText("a b") + "x"},
+		"multiple pastes":   {input: typeText("a b") + " " + typeText("c d"), expected: typeText("a b") + "x" + typeText("c d")},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := replaceKeys(replacer, testData.input)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestKeys_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keys []Key
+
+		expected string
+	}{
+		"nothing":              {keys: []Key{}, expected: ""},
+		"keys":                 {keys: []Key{KeyTab, KeyDown, KeyEnter}, expected: keySeparator + "\t" + arrowDown + "\r"},
+		"text":                 {keys: []Key{"jj"}, expected: keySeparator + "j" + keySeparator + "j" + keySeparator},
+		"text and keys":        {keys: []Key{"a b", KeyCtrlC}, expected: keySeparator + "a" + keySeparator + " b" + keySeparator + "\x03"},
+		"paste":                {keys: []Key{Paste("a b"), KeyEnter}, expected: keySeparator + pasteStart + "a b" + pasteEnd + "\r"},
+		"escape":               {keys: []Key{KeyEsc, "q"}, expected: keySeparator + "\x1b" + keySeparator + "q" + keySeparator},
+		"control characters":   {keys: []Key{"\t\r"}, expected: keySeparator + "\t\r"},
+		"multi-byte character": {keys: []Key{"ü"}, expected: keySeparator + "ü" + keySeparator},
+		"nested keys": {
+			keys:     []Key{"f", Keys("o", KeyDown, Paste("o b")), Keys(KeyTab)},
+			expected: keySeparator + "f" + keySeparator + keySeparator + "o" + keySeparator + arrowDown + pasteStart + "o b" + pasteEnd + keySeparator + "\t",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := Keys(testData.keys...)
+
+			// Assert
+			assert.Equal(t, Key(testData.expected), result)
+		})
+	}
+}
+
+func TestSplitInput_SplitsAfterLoneEscapes(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+
+		expected []string
+	}{
+		"nothing":           {input: "", expected: []string{""}},
+		"no escape":         {input: arrowDown + "a", expected: []string{arrowDown + "a"}},
+		"escape at the end": {input: "a" + escapeKey, expected: []string{"a\x1b"}},
+		"escapes":           {input: escapeKey + escapeKey + arrowDown, expected: []string{"\x1b", "\x1b", arrowDown}},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := splitInput(testData.input)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestNewKeyBindings_ReturnsErrorOnNoUsableKey(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Select.Down = key.NewBinding(key.WithKeys("f13"))
+
+	// Act
+	result, err := newKeyBindings(keyMap)
+
+	// Assert
+	require.ErrorIs(t, err, errNoUsableKey)
+	assert.Nil(t, result)
+}
+
+func TestKeyBindings_Translate_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Input.Next = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Select.Down = key.NewBinding(key.WithKeys("j"))
+	keyMap.Select.Up = key.NewBinding(key.WithKeys("k"))
+	keyMap.MultiSelect.Down = key.NewBinding(key.WithKeys("ctrl+n"))
+	keyMap.MultiSelect.Toggle = key.NewBinding(key.WithKeys("x"))
+	keyMap.Confirm.Toggle = key.NewBinding(key.WithKeys("t"))
+	keyMap.Text.NewLine = key.NewBinding(key.WithKeys("alt+enter"))
+	keyMap.Note.Next = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Note.Submit = key.NewBinding(key.WithKeys("tab"))
+	keyMap.Quit = key.NewBinding(key.WithKeys("esc"))
+
+	keys, err := newKeyBindings(keyMap)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		keys     keyBindings
+		response *response
+		answer   string
+
+		expected string
+	}{
+		"default abort": {
+			response: &response{field: fieldAbort, submitCharacterOverride: string(KeyCtrlC)},
+			expected: "<ctrl+c>",
+		},
+		"abort": {
+			keys:     keys,
+			response: &response{field: fieldAbort, submitCharacterOverride: string(KeyCtrlC)},
+			expected: "<esc>",
+		},
+		"default input": {
+			response: &response{field: fieldInput},
+			answer:   "hello",
+			expected: "hello<submit>",
+		},
+		"default text": {
+			response: &response{field: fieldText},
+			answer:   "a" + newLine + "b",
+			expected: "a<newline>b<submit>",
+		},
+		"default input replacing existing value": {
+			response: &response{field: fieldInput, replaceExisting: true},
+			answer:   "hello",
+			expected: "<end><clear>hello<submit>",
+		},
+		"default text replacing existing value": {
+			response: &response{field: fieldText, replaceExisting: true},
+			answer:   "a",
+			expected: "<input-end>" + strings.Repeat("<clear><backspace>", textMaxLines-1) + "<clear>a<submit>",
+		},
+		"default accepted value": {
+			response: &response{},
+			expected: "<submit>",
+		},
+		"default select": {
+			response: &response{field: fieldSelect},
+			answer:   arrowDown,
+			expected: "<down><submit>",
+		},
+		"keystrokes": {
+			keys:     keys,
+			response: &response{field: fieldKeystrokes},
+			answer:   string(Keys("a", KeyDown, KeyEnter)),
+			expected: "a<down><submit>",
+		},
+		"input": {
+			keys:     keys,
+			response: &response{field: fieldInput},
+			answer:   "hello down",
+			expected: "hello down<tab>",
+		},
+		"text replacing existing value": {
+			keys:     keys,
+			response: &response{field: fieldText, replaceExisting: true},
+			answer:   "a" + newLine + "b",
+			expected: "<input-end>" + strings.Repeat("<clear><backspace>", textMaxLines-1) + "<clear>a\x1b<submit>b<submit>",
+		},
+		"text": {
+			keys:     keys,
+			response: &response{field: fieldText},
+			answer:   "a" + newLine + "b",
+			expected: "a\x1b<submit>b<submit>",
+		},
+		"select replacing existing value": {
+			keys:     keys,
+			response: &response{field: fieldSelect, replaceExisting: true},
+			answer:   arrowDown,
+			expected: "j<submit>",
+		},
+		"select": {
+			keys:     keys,
+			response: &response{field: fieldSelect},
+			answer:   arrowDown + arrowDown + arrowUp,
+			expected: "jjk<submit>",
+		},
+		"multi select": {
+			keys:     keys,
+			response: &response{field: fieldMultiSelect},
+			answer:   selectOption + arrowDown + selectOption,
+			expected: "x\x0ex<submit>",
+		},
+		"affirmative confirm": {
+			keys:     keys,
+			response: &response{field: fieldConfirm},
+			answer:   arrowRight,
+			expected: "t<submit>",
+		},
+		"negative confirm": {
+			keys:     keys,
+			response: &response{field: fieldConfirm},
+			expected: "<submit>",
+		},
+		"default note": {
+			response: &response{field: fieldNote},
+			expected: "<submit>",
+		},
+		"note": {
+			keys:     keys,
+			response: &response{field: fieldNote},
+			expected: "<tab>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.keys.translate(testData.response, testData.answer)
+
+			// Assert
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
+
+func TestKeyBindings_Previous_ReturnsExpectedInput(t *testing.T) {
+	t.Parallel()
+
+	keyMap := huh.NewDefaultKeyMap()
+	keyMap.Input.Prev = key.NewBinding(key.WithKeys("ctrl+p"))
+
+	keys, err := newKeyBindings(keyMap)
+	require.NoError(t, err)
+
+	tests := map[string]struct {
+		keys     keyBindings
+		response *response
+
+		expected string
+	}{
+		"default input": {
+			response: &response{field: fieldInput},
+			expected: "<shift+tab>",
+		},
+		"input": {
+			keys:     keys,
+			response: &response{field: fieldInput},
+			expected: "\x10",
+		},
+		"select": {
+			keys:     keys,
+			response: &response{field: fieldSelect},
+			expected: "<shift+tab>",
+		},
+		"keystrokes": {
+			keys:     keys,
+			response: &response{field: fieldKeystrokes},
+			expected: "<shift+tab>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.keys.previous(testData.response)
+
+			// Assert
+			assert.Equal(t, testData.expected, readableReplacer.Replace(result))
+		})
+	}
+}
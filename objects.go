@@ -3,7 +3,10 @@ package huhtest
 import (
 	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"slices"
+	"strconv"
 	"strings"
 )
 
@@ -43,7 +46,8 @@ func newResponses() *responses {
 // responses is a collection of responses that the user has registered in a Responder,
 // with convenient methods that make it easier to retrieve a desired response.
 //
-// It keeps 3 separate maps with questions that are matched using different questionMatchType options.
+// It keeps 3 separate maps with questions that are matched using different questionMatchType options. Questions
+// that can partially match a line are also kept in order of registration, so that matching is deterministic.
 type responses struct {
 	// exactQuestions is for questionMatchExact questions and should be evaluated first
 	exactQuestions map[string]*response
@@ -53,61 +57,128 @@ type responses struct {
 	// for matching.
 	substringQuestions map[string]*response
 
+	// substringOrder contains the keys of substringQuestions in the order they were first added
+	substringOrder []string
+
 	// regexQuestions saves the responses and the string version of the regex, because regexp.Regexp objects
 	// can't be map keys.
 	regexQuestions map[string]*response
 
+	// regexOrder contains the keys of regexQuestions in the order they were first added
+	regexOrder []string
+
 	// regexCache keeps track of the actual Regexp objects so that we don't have to
 	// compile them on every find call..
 	regexCache map[string]*regexp.Regexp
 }
 
-// find traverses the 3 question types for a match with the given line. The order is from easy to
-// difficult, starting with exact matches and ending with regexp. It returns a response if found,
-// the question it matched with and a boolean that indicates whether a question was found.
-func (q *responses) find(line string) (*response, string, bool) {
+// questionMatch is a registered question that matches a line, along with its response
+type questionMatch struct {
+	question string
+	response *response
+}
+
+// matches returns every question that matches the given line, in order of priority. The order is from easy
+// to difficult, starting with exact matches and ending with regexp. Within a question type, questions that were
+// registered first come first.
+func (q *responses) matches(line string) []questionMatch {
+	var result []questionMatch
+
 	if response, ok := q.exactQuestions[line]; ok {
-		return response, line, true
+		result = append(result, questionMatch{question: line, response: response})
 	}
 
-	for question, response := range q.substringQuestions {
+	for _, question := range q.substringOrder {
 		if strings.Contains(line, question) {
-			return response, question, true
+			result = append(result, questionMatch{question: question, response: q.substringQuestions[question]})
 		}
 	}
 
-	for question, response := range q.regexQuestions {
+	for _, question := range q.regexOrder {
 		if q.regexCache[question].MatchString(line) {
-			return response, question, true
+			result = append(result, questionMatch{question: question, response: q.regexQuestions[question]})
+		}
+	}
+
+	return result
+}
+
+// find returns the response of the question with the highest priority that matches the given line, see matches.
+// It returns a response if found, the question it matched with and a boolean that indicates whether a question
+// was found.
+func (q *responses) find(line string) (*response, string, bool) {
+	matches := q.matches(line)
+	if len(matches) == 0 {
+		return nil, "", false
+	}
+
+	return matches[0].response, matches[0].question, true
+}
+
+// errAmbiguousMatch is returned by checkAmbiguity if a line matches more than one question.
+var errAmbiguousMatch = errors.New("matches more than one question")
+
+// checkAmbiguity returns errAmbiguousMatch listing the matching questions if more than one question
+// matches the given line.
+func (q *responses) checkAmbiguity(line string) error {
+	matches := q.matches(line)
+	if len(matches) < 2 {
+		return nil
+	}
+
+	questions := make([]string, len(matches))
+	for index, match := range matches {
+		questions[index] = fmt.Sprintf("%q", match.question)
+	}
+
+	return fmt.Errorf("%q %w: %s", line, errAmbiguousMatch, strings.Join(questions, ", "))
+}
+
+// verify checks whether all responses have been used as often as expected and returns an error listing
+// every question that hasn't, sorted by question.
+func (q *responses) verify() error {
+	var errs []error
+
+	for _, questions := range []map[string]*response{q.exactQuestions, q.substringQuestions, q.regexQuestions} {
+		for question, response := range questions {
+			if err := response.verify(); err != nil {
+				errs = append(errs, fmt.Errorf("%q: %w", question, err))
+			}
 		}
 	}
 
-	return nil, "", false
+	slices.SortFunc(errs, func(a, b error) int { return strings.Compare(a.Error(), b.Error()) })
+
+	return errors.Join(errs...)
 }
 
 // add sorts a new question into the relevant maps, and will compile a regexp into the cache
 // list if one is given. Since this code is unexported, we've opted to let it panic on an
 // unknown questionMatchType instead or eturning an error, as it should be near impossible to
-// trigger that path.
+// trigger that path. Responses to a question that was added before are combined, see combine.
 func (q *responses) add(question string, matchType questionMatchType, res response) {
 	switch matchType {
 	case questionMatchExact:
 		if existing, ok := q.exactQuestions[question]; ok {
-			res.answers = append(existing.answers, res.answers...)
+			combine(question, existing, &res)
 		}
 
 		q.exactQuestions[question] = &res
 
 	case questionMatchSubstring:
 		if existing, ok := q.substringQuestions[question]; ok {
-			res.answers = append(existing.answers, res.answers...)
+			combine(question, existing, &res)
+		} else {
+			q.substringOrder = append(q.substringOrder, question)
 		}
 
 		q.substringQuestions[question] = &res
 
 	case questionMatchRegexp:
 		if existing, ok := q.regexQuestions[question]; ok {
-			res.answers = append(existing.answers, res.answers...)
+			combine(question, existing, &res)
+		} else {
+			q.regexOrder = append(q.regexOrder, question)
 		}
 
 		q.regexQuestions[question] = &res
@@ -118,6 +189,19 @@ func (q *responses) add(question string, matchType questionMatchType, res respon
 	}
 }
 
+// combine puts the answers and follow-ups of an existing response to the same question in front of those of the new
+// response. It panics if the responses aren't of the same kind, as the answers of one can't be given the way the other
+// gives them and the new response would silently change how the existing answers are given. Like an unknown
+// questionMatchType, this is a mistake in the test that should be fixed right away.
+func combine(question string, existing *response, res *response) {
+	if !existing.sameKind(res) {
+		panic(fmt.Sprintf("%q: responses to the same question have to be added using the same method", question))
+	}
+
+	res.answers = append(existing.answers, res.answers...)
+	res.followUps = append(existing.followUps, res.followUps...)
+}
+
 // response contains a list of answers that should be returned in order. It also keeps
 // track of how many times it;s been called and how many times we expect it to be called.
 type response struct {
@@ -125,6 +209,23 @@ type response struct {
 	// being repeated
 	answers []string
 
+	// field is the type of field this response answers, which dictates the key bindings that apply
+	field fieldType
+
+	// resolve is used if non-nil to turn a picked answer into the actual input, based on what the field that the
+	// question belongs to looks like. This allows answers that depend on what's rendered, like option labels.
+	resolve func(answer string, field fieldView) (string, error)
+
+	// steps is used if non-nil to answer a field over multiple frames, for fields that need to be looked at again
+	// after some keys have been sent. It's given the picked answer and returns the stepper that answers the field.
+	steps func(answer string) (stepper, error)
+
+	// followUps are typed into the field if the form rejects an answer with a validation error, see followUp
+	followUps []*followUp
+
+	// replaceExisting deletes the value of the field before an answer is typed into it
+	replaceExisting bool
+
 	// submitCharacter is used if non-empty, as some questions may get tangled if we use the defaultSubmit
 	submitCharacterOverride string
 
@@ -132,6 +233,93 @@ type response struct {
 	expectedTimes int
 }
 
+// stepper answers a field step by step, see response.steps.
+type stepper interface {
+	// next returns the input to send for the current lines of the field and whether this is the last step,
+	// it may return no input to wait for the field to change.
+	next(field []string) (string, bool, error)
+}
+
+// pendingSteps is a response that's being answered in steps, along with its stepper
+type pendingSteps struct {
+	response *response
+	steps    stepper
+}
+
+// followUp is an answer to a field that has rejected the previous answer with a validation error containing the message
+type followUp struct {
+	message string
+	answer  string
+	used    bool
+}
+
+// errNoFollowUp is returned by followUp if none of the follow-up answers are left for a validation error.
+var errNoFollowUp = errors.New("no follow-up answer for validation error")
+
+// followUp returns the first unused follow-up answer for the given validation error and marks it as used, so
+// that a follow-up that's rejected as well doesn't get typed again.
+func (q *response) followUp(validationError string) (string, error) {
+	for _, followUp := range q.followUps {
+		if !followUp.used && strings.Contains(validationError, followUp.message) {
+			followUp.used = true
+			return followUp.answer, nil
+		}
+	}
+
+	return "", fmt.Errorf("%w %q", errNoFollowUp, validationError)
+}
+
+// goBackMarker starts answers that move back to a previous field instead of answering the question, see
+// Responder.GoBack and Responder.GoBackTo. It can't be typed, so it's never part of a regular answer.
+const goBackMarker = "\x00"
+
+// goBack moves back through the fields of a form until it arrives at a previous field.
+type goBack struct {
+	// fields is the amount of fields that are left to move back, it's only used if there's no target
+	fields int
+
+	// target is the question of the field to move back to
+	target string
+
+	// previous is the input that moves back to the previous field
+	previous string
+}
+
+// joinGoBack stores a goBack in an answer, see goBackMarker.
+func joinGoBack(back goBack) string {
+	if back.target != "" {
+		return goBackMarker + labelSeparator + back.target
+	}
+
+	return goBackMarker + strconv.Itoa(back.fields)
+}
+
+// parseGoBack returns the goBack stored in an answer by joinGoBack, it returns false if the answer is a regular one.
+func parseGoBack(answer string) (goBack, bool) {
+	value, ok := strings.CutPrefix(answer, goBackMarker)
+	if !ok {
+		return goBack{}, false
+	}
+
+	if target, ok := strings.CutPrefix(value, labelSeparator); ok {
+		return goBack{target: target}, true
+	}
+
+	fields, _ := strconv.Atoi(value)
+
+	return goBack{fields: fields}, true
+}
+
+// arrived returns true if the field with the given question is where the goBack should stop, it's called for every
+// field that gets focus along the way. The question is matched against the target using strings.Contains.
+func (g *goBack) arrived(question string) bool {
+	if g.target != "" {
+		return strings.Contains(question, g.target)
+	}
+
+	return g.fields <= 0
+}
+
 // errRanOutOfResponses may be returned by pickAnswer if an expectedTimes is set.
 var errRanOutOfResponses = errors.New("ran out of responses")
 
@@ -152,11 +340,36 @@ func (q *response) pickAnswer() (string, error) {
 	return q.lastAnswer(), nil
 }
 
+// errResponseNotUsed may be returned by verify if a response was used fewer times than expected.
+var errResponseNotUsed = errors.New("response was not used enough")
+
+// verify returns errResponseNotUsed if the response was used fewer times than the expectedTimes, or
+// if it was never used at all when no expectedTimes is set.
+func (q *response) verify() error {
+	expected := max(q.expectedTimes, 1)
+
+	if q.actualTimes < expected {
+		return fmt.Errorf("called %d/%d times: %w", q.actualTimes, expected, errResponseNotUsed)
+	}
+
+	return nil
+}
+
 // lastAnswer is a convenience method for getting the final answer in the answers slice.
 func (q *response) lastAnswer() string {
 	return q.answers[len(q.answers)-1]
 }
 
+// sameKind returns true if the responses give their answers the same way, which is the case if they were added using
+// the same method. The functions that resolve the answers are compared by their code, as the functions of the
+// responses that a method adds come from the same function literal.
+func (q *response) sameKind(other *response) bool {
+	return q.field == other.field &&
+		q.submitCharacterOverride == other.submitCharacterOverride &&
+		reflect.ValueOf(q.resolve).Pointer() == reflect.ValueOf(other.resolve).Pointer() &&
+		reflect.ValueOf(q.steps).Pointer() == reflect.ValueOf(other.steps).Pointer()
+}
+
 // submitCharacter is used to catch any special submit situations, such as with select questions
 // that only require a \r and not the \n. If no override character has been defined, defaultSubmit is returned.
 func (q *response) submitCharacter() string {
@@ -166,3 +379,30 @@ func (q *response) submitCharacter() string {
 
 	return defaultSubmit
 }
+
+// expectedValidationError is a validation error that the form should show for a question, the question is matched
+// against the title of the field and the message against the validation error, both using strings.Contains.
+type expectedValidationError struct {
+	question string
+	message  string
+	shown    bool
+}
+
+// observe marks the expected validation error as shown if it matches the given question and validation error.
+func (e *expectedValidationError) observe(question string, validationError string) {
+	if strings.Contains(question, e.question) && strings.Contains(validationError, e.message) {
+		e.shown = true
+	}
+}
+
+// errValidationErrorNotShown may be returned by verify if the form didn't show an expected validation error.
+var errValidationErrorNotShown = errors.New("validation error was not shown")
+
+// verify returns errValidationErrorNotShown if the expected validation error hasn't been observed.
+func (e *expectedValidationError) verify() error {
+	if !e.shown {
+		return fmt.Errorf("%q: %q %w", e.question, e.message, errValidationErrorNotShown)
+	}
+
+	return nil
+}
@@ -36,6 +36,7 @@ func TestResponses_Find_ReturnsExpectedResponses(t *testing.T) {
 		"substring response is found correctly": {
 			responses: &responses{
 				substringQuestions: map[string]*response{"Hello": dummyResponse},
+				substringOrder:     []string{"Hello"},
 			},
 			line: "Hello World?",
 
@@ -45,6 +46,7 @@ func TestResponses_Find_ReturnsExpectedResponses(t *testing.T) {
 		"regexp response is found correctly": {
 			responses: &responses{
 				regexQuestions: map[string]*response{`Hel{2}o [Ww]orl.\?`: dummyResponse},
+				regexOrder:     []string{`Hel{2}o [Ww]orl.\?`},
 				regexCache:     map[string]*regexp.Regexp{`Hel{2}o [Ww]orl.\?`: regexp.MustCompile(`Hel{2}o [Ww]orl.\?`)},
 			},
 			line: "Hello World?",
@@ -83,6 +85,100 @@ func TestResponses_Find_ReturnsExpectedResponses(t *testing.T) {
 	}
 }
 
+func TestResponses_Find_PrefersQuestionsInOrderOfRegistration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		questions  []string
+		matchTypes []questionMatchType
+		line       string
+
+		expectedQuestion string
+	}{
+		"first substring wins": {
+			questions:        []string{"Name", "Project Name"},
+			matchTypes:       []questionMatchType{questionMatchSubstring, questionMatchSubstring},
+			line:             "Project Name?",
+			expectedQuestion: "Name",
+		},
+		"first substring wins in reverse": {
+			questions:        []string{"Project Name", "Name"},
+			matchTypes:       []questionMatchType{questionMatchSubstring, questionMatchSubstring},
+			line:             "Project Name?",
+			expectedQuestion: "Project Name",
+		},
+		"first regexp wins": {
+			questions:        []string{"^P", "Name"},
+			matchTypes:       []questionMatchType{questionMatchRegexp, questionMatchRegexp},
+			line:             "Project Name?",
+			expectedQuestion: "^P",
+		},
+		"exact before substring": {
+			questions:        []string{"Name", "Project Name?"},
+			matchTypes:       []questionMatchType{questionMatchSubstring, questionMatchExact},
+			line:             "Project Name?",
+			expectedQuestion: "Project Name?",
+		},
+		"substring before regexp": {
+			questions:        []string{"Name$", "Project"},
+			matchTypes:       []questionMatchType{questionMatchRegexp, questionMatchSubstring},
+			line:             "Project Name",
+			expectedQuestion: "Project",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			res := newResponses()
+
+			for index, question := range testData.questions {
+				res.add(question, testData.matchTypes[index], *dummyResponse)
+			}
+
+			// Act
+			for range 20 {
+				_, question, ok := res.find(testData.line)
+
+				// Assert
+				require.True(t, ok)
+				assert.Equal(t, testData.expectedQuestion, question)
+			}
+		})
+	}
+}
+
+func TestResponses_CheckAmbiguity_ReturnsErrorOnMultipleMatches(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	res := newResponses()
+	res.add("Name", questionMatchSubstring, *dummyResponse)
+	res.add("Project Name", questionMatchSubstring, *dummyResponse)
+	res.add("^Project", questionMatchRegexp, *dummyResponse)
+
+	// Act
+	err := res.checkAmbiguity("Project Name?")
+
+	// Assert
+	require.ErrorIs(t, err, errAmbiguousMatch)
+	assert.EqualError(t, err, `"Project Name?" matches more than one question: "Name", "Project Name", "^Project"`)
+}
+
+func TestResponses_CheckAmbiguity_ReturnsNilOnSingleMatch(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	res := newResponses()
+	res.add("Name", questionMatchSubstring, *dummyResponse)
+	res.add("Age", questionMatchSubstring, *dummyResponse)
+
+	// Act
+	err := res.checkAmbiguity("Name?")
+
+	// Assert
+	require.NoError(t, err)
+}
+
 func TestResponses_Add_AddsExactQuestionToExactMap(t *testing.T) {
 	t.Parallel()
 	// Arrange
@@ -188,6 +284,64 @@ func TestResponses_Add_PanicsOnUnrecognisedQuestionType(t *testing.T) {
 	require.PanicsWithValue(t, "unknown question match type", result)
 }
 
+func TestResponses_Add_PanicsOnResponsesOfDifferentKinds(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		existing response
+		added    response
+	}{
+		"different fields": {
+			existing: response{field: fieldInput, answers: []string{"foo"}},
+			added:    response{field: fieldText, answers: []string{"bar"}},
+		},
+		"different resolvers": {
+			existing: response{field: fieldSelect, resolve: resolveSelectIndex, answers: []string{"1"}},
+			added:    response{field: fieldSelect, resolve: resolveSelectLabel, answers: []string{"b"}},
+		},
+		"resolver and none": {
+			existing: response{field: fieldSelect, resolve: resolveSelectIndex, answers: []string{"1"}},
+			added:    response{field: fieldSelect, answers: []string{""}},
+		},
+		"different submit": {
+			existing: response{field: fieldAbort, submitCharacterOverride: string(KeyCtrlC)},
+			added:    response{field: fieldAbort, answers: []string{"foo"}},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			responses := newResponses()
+			responses.add("How many fingers?", questionMatchExact, testData.existing)
+
+			// Act
+			result := func() { responses.add("How many fingers?", questionMatchExact, testData.added) }
+
+			// Assert
+			require.PanicsWithValue(t, `"How many fingers?": responses to the same question have to be added using the same method`, result)
+		})
+	}
+}
+
+func TestResponses_Add_CombinesResponsesAddedUsingTheSameMethod(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	responder := NewResponder().
+		AddMultiSelect("Which toppings?", []int{0}).
+		AddMultiSelect("Which toppings?", []int{1, 2})
+
+	// Act
+	responder.saveResponse()
+
+	// Assert
+	require.NotEmpty(t, responder.responses.substringQuestions["Which toppings?"])
+
+	answers := responder.responses.substringQuestions["Which toppings?"].answers
+	assert.Equal(t, []string{"0", joinIndexes([]int{1, 2})}, answers)
+}
+
 func TestResponse_PickAnswer_ReturnsErrorOnRanOutOfAttempts(t *testing.T) {
 	t.Parallel()
 
@@ -307,3 +461,258 @@ func TestResponse_SubmitCharacter_ReturnsExpectedCharacter(t *testing.T) {
 		})
 	}
 }
+
+func TestResponse_Verify_ReturnsExpectedError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		response *response
+
+		expected error
+	}{
+		"used once without expected times": {
+			response: &response{actualTimes: 1},
+			expected: nil,
+		},
+		"used often without expected times": {
+			response: &response{actualTimes: 5},
+			expected: nil,
+		},
+		"used as often as expected": {
+			response: &response{actualTimes: 3, expectedTimes: 3},
+			expected: nil,
+		},
+		"never used": {
+			response: &response{},
+			expected: fmt.Errorf("called 0/1 times: %w", errResponseNotUsed),
+		},
+		"used less than expected": {
+			response: &response{actualTimes: 2, expectedTimes: 3},
+			expected: fmt.Errorf("called 2/3 times: %w", errResponseNotUsed),
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			err := testData.response.verify()
+
+			// Assert
+			assert.Equal(t, testData.expected, err)
+		})
+	}
+}
+
+func TestResponses_Add_CombinesFollowUps(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	res := newResponses()
+	question := "Name?"
+
+	first := &followUp{message: "short", answer: "Gopher"}
+	second := &followUp{message: "long", answer: "Go"}
+
+	res.add(question, questionMatchSubstring, response{answers: []string{"foo"}, followUps: []*followUp{first}})
+
+	// Act
+	res.add(question, questionMatchSubstring, response{answers: []string{"bar"}, followUps: []*followUp{second}})
+
+	// Assert
+	assert.Equal(t, []*followUp{first, second}, res.substringQuestions[question].followUps)
+}
+
+func TestResponse_FollowUp_ReturnsFollowUpsOnce(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	response := &response{
+		followUps: []*followUp{
+			{message: "too short", answer: "a"},
+			{message: "too long", answer: "b"},
+			{message: "too short", answer: "c"},
+		},
+	}
+
+	// Act
+	first, firstErr := response.followUp("name is too short")
+	second, secondErr := response.followUp("name is too short")
+	third, thirdErr := response.followUp("name is too short")
+
+	// Assert
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	require.ErrorIs(t, thirdErr, errNoFollowUp)
+
+	assert.Equal(t, "a", first)
+	assert.Equal(t, "c", second)
+	assert.Empty(t, third)
+}
+
+func TestExpectedValidationError_Verify_ReturnsExpectedError(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		question        string
+		validationError string
+
+		expected error
+	}{
+		"shown": {
+			question:        "What's your name?",
+			validationError: "name is too short",
+			expected:        nil,
+		},
+		"shown for another question": {
+			question:        "What's your age?",
+			validationError: "name is too short",
+			expected:        fmt.Errorf("%q: %q %w", "name", "too short", errValidationErrorNotShown),
+		},
+		"another error shown": {
+			question:        "What's your name?",
+			validationError: "name is too long",
+			expected:        fmt.Errorf("%q: %q %w", "name", "too short", errValidationErrorNotShown),
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			expected := &expectedValidationError{question: "name", message: "too short"}
+
+			expected.observe(testData.question, testData.validationError)
+
+			// Act
+			err := expected.verify()
+
+			// Assert
+			assert.Equal(t, testData.expected, err)
+		})
+	}
+}
+
+func TestResponses_Verify_ListsUnusedQuestionsInOrder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	res := &responses{
+		exactQuestions:     map[string]*response{"b?": {}, "used?": {actualTimes: 1}},
+		substringQuestions: map[string]*response{"c": {actualTimes: 1, expectedTimes: 2}},
+		regexQuestions:     map[string]*response{"^a": {}},
+	}
+
+	// Act
+	err := res.verify()
+
+	// Assert
+	require.ErrorIs(t, err, errResponseNotUsed)
+	assert.EqualError(t, err, "\"^a\": called 0/1 times: response was not used enough\n"+
+		"\"b?\": called 0/1 times: response was not used enough\n"+
+		"\"c\": called 1/2 times: response was not used enough")
+}
+
+func TestResponses_Verify_ReturnsNilIfAllQuestionsWereUsed(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	res := newResponses()
+	res.add("a?", questionMatchExact, response{actualTimes: 1})
+
+	// Act
+	err := res.verify()
+
+	// Assert
+	require.NoError(t, err)
+}
+
+func TestParseGoBack_ReturnsJoinedGoBack(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		back goBack
+	}{
+		"fields": {
+			back: goBack{fields: 3},
+		},
+		"target": {
+			back: goBack{target: "What's your name?"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, ok := parseGoBack(joinGoBack(testData.back))
+
+			// Assert
+			assert.True(t, ok)
+			assert.Equal(t, testData.back, result)
+		})
+	}
+}
+
+func TestParseGoBack_ReturnsFalseOnRegularAnswers(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		answer string
+	}{
+		"empty":   {answer: ""},
+		"text":    {answer: "John"},
+		"indexes": {answer: joinIndexes([]int{1, 2})},
+		"labels":  {answer: "a" + labelSeparator + "b"},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			_, ok := parseGoBack(testData.answer)
+
+			// Assert
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestGoBack_Arrived_ReturnsExpectedResult(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		back     goBack
+		question string
+
+		expected bool
+	}{
+		"fields left": {
+			back:     goBack{fields: 1},
+			question: "What's your name?",
+			expected: false,
+		},
+		"no fields left": {
+			back:     goBack{fields: 0},
+			question: "What's your name?",
+			expected: true,
+		},
+		"other question": {
+			back:     goBack{target: "name"},
+			question: "What's your age?",
+			expected: false,
+		},
+		"target question": {
+			back:     goBack{target: "name"},
+			question: "What's your name?",
+			expected: true,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.back.arrived(testData.question)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
@@ -0,0 +1,495 @@
+package huhtest
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"unicode/utf8"
+)
+
+// Recorder records an interactive run of a form and turns it into the Responder that answers the form the same way,
+// so that it doesn't have to be written by hand. It keeps track of the focused field like the Responder does, and
+// attributes the keys that are pressed to it.
+//
+// Usage:
+//
+//	recorder := huhtest.NewRecorder()
+//	input, output := recorder.Wrap(os.Stdin, os.Stdout)
+//
+//	err := form.WithInput(input).WithOutput(output).Run()
+//
+//	fmt.Println(recorder.Code())
+type Recorder struct {
+	// screen contains everything the form has rendered, lock guards it along with the rest of the Recorder as the
+	// input and output of a form are used in separate goroutines
+	screen *screen
+	lock   sync.Mutex
+
+	// focus is the question of the field that currently has focus, or empty if no field has focus
+	focus string
+
+	// focusTop is the row that the focused field starts at
+	focusTop int
+
+	// view is what the focused field currently looks like
+	view fieldView
+
+	// note is true if the focused field is a huh.Note
+	note bool
+
+	// steps contain the keys that were pressed, grouped by the field they were pressed at
+	steps []*recordedStep
+}
+
+// NewRecorder returns a Recorder, use Wrap to record the input and output of a form.
+func NewRecorder() *Recorder {
+	return &Recorder{screen: newScreen()}
+}
+
+// Wrap returns the input and output to run a form with, which pass everything on to the given input and output while
+// recording it. If they are terminals, like os.Stdin and os.Stdout, the returned input and output can be used as
+// terminals as well, so that the form is rendered like it normally would be.
+func (r *Recorder) Wrap(input io.Reader, output io.Writer) (io.Reader, io.Writer) {
+	recordedIn := &recordedInput{input: input, recorder: r}
+	recordedOut := &recordedOutput{output: output, recorder: r}
+
+	var resultIn io.Reader = recordedIn
+	if file, ok := input.(terminal); ok {
+		resultIn = &recordedInputTerminal{recordedInput: recordedIn, terminal: file}
+	}
+
+	var resultOut io.Writer = recordedOut
+	if file, ok := output.(terminal); ok {
+		resultOut = &recordedOutputTerminal{recordedOutput: recordedOut, terminal: file}
+	}
+
+	return resultIn, resultOut
+}
+
+// Code returns the Go code of a Responder that answers the questions like they were answered while recording, like:
+//
+//	huhtest.NewResponder().
+//		AddResponse("What's your name?", "Gopher").
+//		AddSelectLabel("Favourite colour?", "Green")
+//
+// Answers are turned into the response that describes them best, based on what the field looked like when it was
+// submitted. Keys that don't translate into another response are added using AddKeys, which sends them exactly
+// as they were pressed. If a question is answered in different ways, all of its answers are added using AddKeys.
+func (r *Recorder) Code() string {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	calls := make([]recordedCall, len(r.steps))
+	for index, step := range r.steps {
+		calls[index] = step.call()
+	}
+
+	// A Responder only keeps the type of the latest response to a question, so mixed responses are sent as keys
+	methods := make(map[string]string)
+	mixed := make(map[string]bool)
+
+	for _, call := range calls {
+		if method, ok := methods[call.question]; ok && method != call.method {
+			mixed[call.question] = true
+		}
+
+		methods[call.question] = call.method
+	}
+
+	var result strings.Builder
+	result.WriteString("huhtest.NewResponder()")
+
+	for index, call := range calls {
+		if mixed[call.question] {
+			call = r.steps[index].keysCall()
+		}
+
+		result.WriteString(".\n\t" + call.String())
+	}
+
+	return result.String()
+}
+
+// press records keys that were read from the input of the form, for the field that currently has focus. Keys that
+// are pressed while no field has focus can't be answered by a Responder, so they're left out.
+func (r *Recorder) press(input []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	if r.focus == "" || len(input) == 0 {
+		return
+	}
+
+	keys := string(input)
+
+	// Bubbletea reads a lone escape character as esc if it's the last input that it reads at once
+	if strings.HasSuffix(keys, "\x1b") && !strings.HasSuffix(keys, "\x1b\x1b") {
+		keys += keySeparator
+	}
+
+	if len(r.steps) == 0 || !r.steps[len(r.steps)-1].at(r.focus, r.note) {
+		r.steps = append(r.steps, &recordedStep{question: r.focus, note: r.note, view: r.view})
+	}
+
+	step := r.steps[len(r.steps)-1]
+	step.settled = keys == defaultSubmit && !step.stale
+	step.stale = true
+	step.input += keys
+}
+
+// render records output of the form and finds the field that has focus, the latest step is given what the field
+// looks like if it's still focused.
+func (r *Recorder) render(output []byte) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	_, _ = r.screen.Write(output)
+
+	switch field, top, ok := r.screen.focusedField(); {
+	case ok:
+		title := fieldTitle(field[0])
+
+		// A select that's being filtered shows the filter instead of its title, or behind it once the filter is set
+		if filtered := strings.HasPrefix(title, "/") || strings.HasPrefix(title, r.focus+"/"); !filtered || r.focus == "" || r.note || top != r.focusTop {
+			r.focus = title
+		}
+
+		r.focusTop = top
+		r.view = fieldView{lines: field, styles: r.screen.fieldStyles(top, len(field))}
+		r.note = false
+
+	default:
+		note, _, isNote := r.screen.focusedNote()
+		if !isNote {
+			r.focus = ""
+			return
+		}
+
+		r.focus = strings.TrimSpace(note[0])
+		r.view = fieldView{lines: note}
+		r.note = true
+	}
+
+	if len(r.steps) > 0 && r.steps[len(r.steps)-1].at(r.focus, r.note) {
+		r.steps[len(r.steps)-1].view = r.view
+		r.steps[len(r.steps)-1].stale = false
+	}
+}
+
+// recordedStep contains the keys that were pressed at a field until another field got focus.
+type recordedStep struct {
+	question string
+	note     bool
+
+	// input contains the keys as they were read
+	input string
+
+	// view is what the field looked like after the last key was pressed
+	view fieldView
+
+	// stale is true if keys have been pressed since the view was rendered, as the form may handle several keys
+	// before rendering again
+	stale bool
+
+	// settled is true if the field was submitted with a separate enter after the view was rendered, which means
+	// that the view shows what was submitted
+	settled bool
+}
+
+// at returns true if the step belongs to the given field.
+func (s *recordedStep) at(question string, note bool) bool {
+	return s.question == question && s.note == note
+}
+
+// call returns the response that describes the keys best, depending on what the field looked like.
+func (s *recordedStep) call() recordedCall {
+	keys := splitKeys(s.input)
+
+	switch {
+	case slices.Equal(keys, []string{string(KeyCtrlC)}):
+		return recordedCall{question: s.question, method: "AbortAt"}
+
+	case s.note && slices.Equal(keys, []string{defaultSubmit}):
+		return recordedCall{question: s.question, method: "AddNote"}
+
+	case s.note || len(keys) == 0 || keys[len(keys)-1] != defaultSubmit || slices.Contains(keys[:len(keys)-1], defaultSubmit):
+		return s.keysCall()
+
+	case len(keys) == 1:
+		return recordedCall{question: s.question, method: "AddAcceptDefault"}
+	}
+
+	options := parseOptions(s.view.lines)
+	multi := isMultiSelect(s.view.lines)
+
+	// Typed text is read from the keys, an input may not have rendered all of it before it was submitted
+	if typed, ok := typedText(keys[:len(keys)-1]); ok && typed != "" && len(options) <= 1 && !multi && s.titled() {
+		if _, ok := inputValue(s.view.lines); ok {
+			return recordedCall{question: s.question, method: "AddResponse", arguments: []string{strconv.Quote(typed)}}
+		}
+	}
+
+	if !s.settled {
+		return s.keysCall()
+	}
+
+	if buttons, ok := findButtons(s.view); ok {
+		answer := "huhtest.ConfirmNegative"
+		if buttons[0].focused {
+			answer = "huhtest.ConfirmAffirm"
+		}
+
+		return recordedCall{question: s.question, method: "AddConfirm", arguments: []string{answer}}
+	}
+
+	switch {
+	case multi && !slices.Contains(keys, "/"):
+		var labels []string
+
+		for _, option := range options {
+			if option.selected {
+				labels = append(labels, strconv.Quote(option.label))
+			}
+		}
+
+		if len(labels) == 0 {
+			return recordedCall{question: s.question, method: "AddMultiSelectNone"}
+		}
+
+		return recordedCall{question: s.question, method: "AddMultiSelectLabels", arguments: []string{"[]string{" + strings.Join(labels, ", ") + "}"}}
+
+	case len(options) > 1 && !multi:
+		for _, option := range options {
+			if option.cursor {
+				return recordedCall{question: s.question, method: "AddSelectLabel", arguments: []string{strconv.Quote(option.label)}}
+			}
+		}
+	}
+
+	return s.keysCall()
+}
+
+// titled returns true if the view shows the question as its title, rather than the filter of a select.
+func (s *recordedStep) titled() bool {
+	return len(s.view.lines) > 0 && fieldTitle(s.view.lines[0]) == s.question
+}
+
+// keysCall returns the response that sends the keys exactly as they were pressed.
+func (s *recordedStep) keysCall() recordedCall {
+	return recordedCall{question: s.question, method: "AddKeys", arguments: []string{keysCode(splitKeys(s.input))}}
+}
+
+// recordedCall is a call to one of the methods of a Responder that adds a response.
+type recordedCall struct {
+	question  string
+	method    string
+	arguments []string
+}
+
+// String returns the call as Go code.
+func (c recordedCall) String() string {
+	arguments := append([]string{strconv.Quote(c.question)}, c.arguments...)
+
+	return c.method + "(" + strings.Join(arguments, ", ") + ")"
+}
+
+// keyNames contains the Go code of the keys that can't be typed, see Keys.
+var keyNames = map[string]string{
+	string(KeyEnter):     "huhtest.KeyEnter",
+	string(KeyTab):       "huhtest.KeyTab",
+	string(KeyShiftTab):  "huhtest.KeyShiftTab",
+	string(KeyEsc):       "huhtest.KeyEsc",
+	string(KeyCtrlC):     "huhtest.KeyCtrlC",
+	string(KeyBackspace): "huhtest.KeyBackspace",
+	string(KeyDelete):    "huhtest.KeyDelete",
+	string(KeySpace):     "huhtest.KeySpace",
+	string(KeyUp):        "huhtest.KeyUp",
+	string(KeyDown):      "huhtest.KeyDown",
+	string(KeyLeft):      "huhtest.KeyLeft",
+	string(KeyRight):     "huhtest.KeyRight",
+	string(KeyHome):      "huhtest.KeyHome",
+	string(KeyEnd):       "huhtest.KeyEnd",
+	string(KeyPageUp):    "huhtest.KeyPageUp",
+	string(KeyPageDown):  "huhtest.KeyPageDown",
+}
+
+// splitKeys splits input that was read from a terminal into separate keys. Pastes and escape sequences are kept
+// together, the keySeparator is left out as it isn't a key.
+func splitKeys(input string) []string {
+	var result []string
+
+	for input != "" {
+		var key string
+
+		switch {
+		case strings.HasPrefix(input, pasteStart):
+			end := strings.Index(input, pasteEnd)
+			if end == -1 {
+				key = input
+			} else {
+				key = input[:end+len(pasteEnd)]
+			}
+
+		case strings.HasPrefix(input, escapeKey):
+			key = escapeKey
+
+		case strings.HasPrefix(input, keySeparator):
+			input = input[len(keySeparator):]
+			continue
+
+		case strings.HasPrefix(input, "\x1b[") || strings.HasPrefix(input, "\x1bO"):
+			// Control sequences end with a byte in the range of @ to ~
+			end := strings.IndexFunc(input[2:], func(character rune) bool { return character >= '@' && character <= '~' })
+			if end == -1 {
+				key = input
+			} else {
+				key = input[:end+3]
+			}
+
+		case strings.HasPrefix(input, "\x1b") && len(input) > 1:
+			// Keys with the alt modifier
+			_, size := utf8.DecodeRuneInString(input[1:])
+			key = input[:size+1]
+
+		default:
+			_, size := utf8.DecodeRuneInString(input)
+			key = input[:size]
+		}
+
+		result = append(result, key)
+		input = input[len(key):]
+	}
+
+	return result
+}
+
+// isTyped returns true if the key is a character that's typed, rather than a key that does something.
+func isTyped(key string) bool {
+	character, size := utf8.DecodeRuneInString(key)
+
+	return size == len(key) && character > 0x1f && character != 0x7f
+}
+
+// typedText returns the text that the keys type into an input, it returns false if the keys do anything else.
+func typedText(keys []string) (string, bool) {
+	var result []rune
+
+	for _, key := range keys {
+		switch {
+		case strings.HasPrefix(key, pasteStart):
+			result = append(result, []rune(strings.TrimSuffix(strings.TrimPrefix(key, pasteStart), pasteEnd))...)
+
+		case isTyped(key):
+			result = append(result, []rune(key)...)
+
+		case key == backspace && len(result) > 0:
+			result = result[:len(result)-1]
+
+		default:
+			return "", false
+		}
+	}
+
+	return string(result), true
+}
+
+// keysCode returns the Go code that combines the keys using Keys, consecutive characters are joined into text.
+func keysCode(keys []string) string {
+	var arguments []string
+	var text strings.Builder
+
+	addText := func() {
+		switch text.String() {
+		case "":
+		case " ":
+			arguments = append(arguments, keyNames[" "])
+		default:
+			arguments = append(arguments, strconv.Quote(text.String()))
+		}
+
+		text.Reset()
+	}
+
+	for _, key := range keys {
+		if isTyped(key) {
+			text.WriteString(key)
+			continue
+		}
+
+		addText()
+
+		switch name, ok := keyNames[key]; {
+		case ok:
+			arguments = append(arguments, name)
+		case strings.HasPrefix(key, pasteStart):
+			arguments = append(arguments, fmt.Sprintf("huhtest.Paste(%s)", strconv.Quote(strings.TrimSuffix(strings.TrimPrefix(key, pasteStart), pasteEnd))))
+		default:
+			arguments = append(arguments, fmt.Sprintf("huhtest.Key(%s)", strconv.Quote(key)))
+		}
+	}
+
+	addText()
+
+	return "huhtest.Keys(" + strings.Join(arguments, ", ") + ")"
+}
+
+// terminal is a file like os.Stdin or os.Stdout. Bubbletea only puts the terminal in raw mode and reads its size if
+// the input and output implement it.
+type terminal interface {
+	io.ReadWriteCloser
+	Name() string
+	Fd() uintptr
+}
+
+// recordedInput passes on the input of a form and records the keys that are read from it.
+type recordedInput struct {
+	input    io.Reader
+	recorder *Recorder
+}
+
+// Read reads from the input and records what's read.
+func (i *recordedInput) Read(p []byte) (int, error) {
+	n, err := i.input.Read(p)
+	i.recorder.press(p[:n])
+
+	return n, err
+}
+
+// recordedInputTerminal is a recordedInput of a terminal, everything except for reading is done by the terminal.
+type recordedInputTerminal struct {
+	*recordedInput
+	terminal terminal
+}
+
+func (i *recordedInputTerminal) Write(p []byte) (int, error) { return i.terminal.Write(p) }
+func (i *recordedInputTerminal) Close() error                { return i.terminal.Close() }
+func (i *recordedInputTerminal) Name() string                { return i.terminal.Name() }
+func (i *recordedInputTerminal) Fd() uintptr                 { return i.terminal.Fd() }
+
+// recordedOutput passes on the output of a form and records what's rendered.
+type recordedOutput struct {
+	output   io.Writer
+	recorder *Recorder
+}
+
+// Write records the output before passing it on, so that keys that are pressed in response to it are recorded
+// for the right field.
+func (o *recordedOutput) Write(p []byte) (int, error) {
+	o.recorder.render(p)
+
+	return o.output.Write(p)
+}
+
+// recordedOutputTerminal is a recordedOutput of a terminal, everything except for writing is done by the terminal.
+type recordedOutputTerminal struct {
+	*recordedOutput
+	terminal terminal
+}
+
+func (o *recordedOutputTerminal) Read(p []byte) (int, error) { return o.terminal.Read(p) }
+func (o *recordedOutputTerminal) Close() error               { return o.terminal.Close() }
+func (o *recordedOutputTerminal) Name() string               { return o.terminal.Name() }
+func (o *recordedOutputTerminal) Fd() uintptr                { return o.terminal.Fd() }
@@ -0,0 +1,342 @@
+package huhtest
+
+import (
+	"io"
+	"os"
+	"testing"
+
+	"github.com/charmbracelet/huh"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSplitKeys_ReturnsSeparateKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		input string
+
+		expected []string
+	}{
+		"characters": {
+			input:    "aé",
+			expected: []string{"a", "é"},
+		},
+		"escape sequences": {
+			input:    "\x1b[A\x1b[3~\x1bOB\r",
+			expected: []string{string(KeyUp), string(KeyDelete), "\x1bOB", string(KeyEnter)},
+		},
+		"paste": {
+			input:    "a" + pasteStart + "up\r" + pasteEnd + "b",
+			expected: []string{"a", pasteStart + "up\r" + pasteEnd, "b"},
+		},
+		"key separators": {
+			input:    "a" + keySeparator + "a" + keySeparator,
+			expected: []string{"a", "a"},
+		},
+		"esc": {
+			input:    escapeKey + "a",
+			expected: []string{string(KeyEsc), "a"},
+		},
+		"alt": {
+			input:    "\x1ba",
+			expected: []string{"\x1ba"},
+		},
+		"nothing": {
+			input:    "",
+			expected: nil,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := splitKeys(testData.input)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestTypedText_ReturnsTextOfKeys(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keys []string
+
+		expectedText string
+		expectedOk   bool
+	}{
+		"characters": {
+			keys:         []string{"a", " ", "b"},
+			expectedText: "a b",
+			expectedOk:   true,
+		},
+		"paste": {
+			keys:         []string{"a", pasteStart + "bc" + pasteEnd},
+			expectedText: "abc",
+			expectedOk:   true,
+		},
+		"backspace": {
+			keys:         []string{"a", "b", backspace, "c"},
+			expectedText: "ac",
+			expectedOk:   true,
+		},
+		"backspace into existing value": {
+			keys:       []string{backspace, "a"},
+			expectedOk: false,
+		},
+		"moving the cursor": {
+			keys:       []string{"a", string(KeyLeft), "b"},
+			expectedOk: false,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			text, ok := typedText(testData.keys)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expectedText, text)
+		})
+	}
+}
+
+func TestKeysCode_ReturnsGoCode(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		keys []string
+
+		expected string
+	}{
+		"text": {
+			keys:     []string{"a", " ", "b", "\""},
+			expected: `huhtest.Keys("a b\"")`,
+		},
+		"named keys": {
+			keys:     []string{"a", string(KeyTab), " ", string(KeyEnter)},
+			expected: `huhtest.Keys("a", huhtest.KeyTab, huhtest.KeySpace, huhtest.KeyEnter)`,
+		},
+		"paste": {
+			keys:     []string{pasteStart + "up" + pasteEnd},
+			expected: `huhtest.Keys(huhtest.Paste("up"))`,
+		},
+		"unnamed keys": {
+			keys:     []string{"\x01", "\x1bOB"},
+			expected: `huhtest.Keys(huhtest.Key("\x01"), huhtest.Key("\x1bOB"))`,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := keysCode(testData.keys)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestRecordedStep_Call_ReturnsResponseOfKeys(t *testing.T) {
+	t.Parallel()
+
+	input := fieldView{lines: []string{"┃ Title?", "┃ > Gopher"}}
+	selectField := fieldView{lines: []string{"┃ Title?", "┃   Red", "┃ > Green"}}
+	multiSelect := fieldView{lines: []string{"┃ Title?", "┃   ✓ Red", "┃ > • Green", "┃   ✓ Blue"}}
+
+	tests := map[string]struct {
+		step recordedStep
+
+		expected string
+	}{
+		"input": {
+			step:     recordedStep{question: "Title?", input: "Go" + pasteStart + "pher" + pasteEnd + "\r", view: input},
+			expected: `AddResponse("Title?", "Gopher")`,
+		},
+		"input with cursor movement": {
+			step:     recordedStep{question: "Title?", input: "Gopher\x1b[DA\r", view: input},
+			expected: `AddKeys("Title?", huhtest.Keys("Gopher", huhtest.KeyLeft, "A", huhtest.KeyEnter))`,
+		},
+		"accept default": {
+			step:     recordedStep{question: "Title?", input: "\r", view: input},
+			expected: `AddAcceptDefault("Title?")`,
+		},
+		"not submitted": {
+			step:     recordedStep{question: "Title?", input: "Gopher" + string(KeyShiftTab), view: input},
+			expected: `AddKeys("Title?", huhtest.Keys("Gopher", huhtest.KeyShiftTab))`,
+		},
+		"submitted twice": {
+			step:     recordedStep{question: "Title?", input: "a\rb\r", view: input},
+			expected: `AddKeys("Title?", huhtest.Keys("a", huhtest.KeyEnter, "b", huhtest.KeyEnter))`,
+		},
+		"abort": {
+			step:     recordedStep{question: "Title?", input: "\x03", view: input},
+			expected: `AbortAt("Title?")`,
+		},
+		"select": {
+			step:     recordedStep{question: "Title?", input: "\x1b[B\r", view: selectField, settled: true},
+			expected: `AddSelectLabel("Title?", "Green")`,
+		},
+		"select that didn't render before it was submitted": {
+			step:     recordedStep{question: "Title?", input: "\x1b[B\r", view: selectField},
+			expected: `AddKeys("Title?", huhtest.Keys(huhtest.KeyDown, huhtest.KeyEnter))`,
+		},
+		"filtered select": {
+			step:     recordedStep{question: "Title?", input: "/gr\r\r", view: fieldView{lines: []string{"┃ Title?/gr", "┃ > Green"}}, settled: true},
+			expected: `AddKeys("Title?", huhtest.Keys("/gr", huhtest.KeyEnter, huhtest.KeyEnter))`,
+		},
+		"multi-select": {
+			step:     recordedStep{question: "Title?", input: " \x1b[B\x1b[B \r", view: multiSelect, settled: true},
+			expected: `AddMultiSelectLabels("Title?", []string{"Red", "Blue"})`,
+		},
+		"multi-select without selection": {
+			step:     recordedStep{question: "Title?", input: " \r", view: fieldView{lines: []string{"┃ Title?", "┃ > • Red"}}, settled: true},
+			expected: `AddMultiSelectNone("Title?")`,
+		},
+		"confirm": {
+			step:     recordedStep{question: "Title?", input: "\x1b[D\r", view: renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmAffirm, false), settled: true},
+			expected: `AddConfirm("Title?", huhtest.ConfirmAffirm)`,
+		},
+		"negative confirm": {
+			step:     recordedStep{question: "Title?", input: "n\r", view: renderButtons(t, huh.ThemeCharm(), "Yes", "No", ConfirmNegative, false), settled: true},
+			expected: `AddConfirm("Title?", huhtest.ConfirmNegative)`,
+		},
+		"confirm without colours": {
+			step:     recordedStep{question: "Title?", input: "\x1b[D\r", view: fieldView{lines: []string{"┃ Title?", "┃", "┃   Yes     No"}}, settled: true},
+			expected: `AddKeys("Title?", huhtest.Keys(huhtest.KeyLeft, huhtest.KeyEnter))`,
+		},
+		"note": {
+			step:     recordedStep{question: "Title", input: "\r", note: true},
+			expected: `AddNote("Title")`,
+		},
+		"note with other keys": {
+			step:     recordedStep{question: "Title", input: "\x1b[B\r", note: true},
+			expected: `AddKeys("Title", huhtest.Keys(huhtest.KeyDown, huhtest.KeyEnter))`,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.step.call()
+
+			// Assert
+			assert.Equal(t, testData.expected, result.String())
+		})
+	}
+}
+
+func TestRecorder_Code_ReturnsResponderOfRecordedKeys(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	recorder := NewRecorder()
+
+	// Act
+	recorder.render([]byte("┃ Name?\r\n┃ >"))
+	recorder.press([]byte("G"))
+	recorder.render([]byte("\r┃ > G"))
+	recorder.press([]byte("o"))
+	recorder.press([]byte("\r"))
+	recorder.render([]byte("\x1b[1A\r\x1b[2K  Name?\r\n\x1b[2K  > Go\r\n\r\n┃ Colour?\r\n┃ > Red\r\n┃   Green"))
+	recorder.press([]byte("/"))
+	recorder.render([]byte("\x1b[2A\r\x1b[2K┃ /"))
+	recorder.press([]byte("g"))
+	recorder.render([]byte("\r\x1b[2K┃ /g\r\n\x1b[2K┃ > Green\r\n\x1b[2K"))
+	recorder.press([]byte(string(KeyShiftTab)))
+	recorder.render([]byte("\x1b[5A\r\x1b[2K┃ Name?\r\n\x1b[2K┃ > Go\r\n\r\n\x1b[2K  Colour?/g\r\n\x1b[2K    Green"))
+	recorder.press([]byte("\r"))
+
+	result := recorder.Code()
+
+	// Assert
+	expected := "huhtest.NewResponder().\n" +
+		"\tAddKeys(\"Name?\", huhtest.Keys(\"Go\", huhtest.KeyEnter)).\n" +
+		"\tAddKeys(\"Colour?\", huhtest.Keys(\"/g\", huhtest.KeyShiftTab)).\n" +
+		"\tAddKeys(\"Name?\", huhtest.Keys(huhtest.KeyEnter))"
+
+	assert.Equal(t, expected, result)
+}
+
+func TestRecorder_Wrap_RecordsFormAnsweredByResponder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?"),
+			huh.NewMultiSelect[string]().Title("Toppings?").Options(huh.NewOptions("Cheese", "Ham", "Olives")...),
+		),
+		huh.NewGroup(
+			huh.NewNote().Title("Almost done").Next(true),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().Title("Are you sure?"),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		AddMultiSelectLabels("Toppings?", []string{"Cheese", "Olives"}).
+		AddNote("Almost done").
+		AddConfirm("Are you sure?", ConfirmAffirm).
+		GuessConfirms()
+
+	stdin, stdout, closer := responder.Start(t, defaultTimeout)
+	defer closer()
+
+	recorder := NewRecorder()
+	input, output := recorder.Wrap(stdin, stdout)
+
+	require.NoError(t, form.WithInput(input).WithOutput(output).Run())
+
+	// Act
+	result := recorder.Code()
+
+	// Assert
+	expected := "huhtest.NewResponder().\n" +
+		"\tAddResponse(\"What's your name?\", \"Gopher\").\n" +
+		"\tAddMultiSelectLabels(\"Toppings?\", []string{\"Cheese\", \"Olives\"}).\n" +
+		"\tAddNote(\"Almost done\").\n" +
+		"\tAddKeys(\"Are you sure?\", huhtest.Keys(huhtest.KeyRight, huhtest.KeyEnter))"
+
+	assert.Equal(t, expected, result)
+}
+
+func TestRecorder_Wrap_KeepsTerminals(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	reader, writer, err := os.Pipe()
+	require.NoError(t, err)
+
+	t.Cleanup(func() {
+		_ = reader.Close()
+		_ = writer.Close()
+	})
+
+	recorder := NewRecorder()
+
+	// Act
+	input, output := recorder.Wrap(reader, writer)
+
+	// Assert
+	if assert.Implements(t, (*terminal)(nil), input) {
+		assert.Equal(t, reader.Fd(), input.(terminal).Fd())
+	}
+
+	if assert.Implements(t, (*terminal)(nil), output) {
+		assert.Equal(t, writer.Fd(), output.(terminal).Fd())
+	}
+
+	input, output = recorder.Wrap(io.MultiReader(reader), io.MultiWriter(writer))
+
+	assert.NotImplements(t, (*terminal)(nil), input)
+	assert.NotImplements(t, (*terminal)(nil), output)
+}
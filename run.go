@@ -0,0 +1,109 @@
+package huhtest
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	testingi "github.com/mitchellh/go-testing-interface"
+)
+
+// defaultRunTimeout is used by RunForm and RunField if no timeout is given using WithTimeout.
+const defaultRunTimeout = 5 * time.Second
+
+// runOptions contains the settings of RunForm and RunField that can be changed using RunOption.
+type runOptions struct {
+	ctx     context.Context
+	timeout time.Duration
+}
+
+// RunOption changes the way RunForm and RunField run a form.
+type RunOption func(*runOptions)
+
+// WithTimeout sets the time after which the form is stopped and the test fails, the default is 5 seconds.
+func WithTimeout(timeout time.Duration) RunOption {
+	return func(options *runOptions) {
+		options.timeout = timeout
+	}
+}
+
+// WithContext sets the context that the form and the Responder run with, they're stopped when it's done. The
+// timeout still applies.
+func WithContext(ctx context.Context) RunOption {
+	return func(options *runOptions) {
+		options.ctx = ctx
+	}
+}
+
+// RunForm runs the form with the responder answering its questions and returns the error of the form. It saves you
+// from having to call Start, deferring the Closer and wiring up the form's input and output. Like with Start, the
+// transcript of the responder and the final screen are logged if the test fails.
+//
+// Usage:
+//
+//	err := huhtest.RunForm(t, myForm, huhtest.NewResponder().
+//	  AddResponse(...).
+//	  AddConfirm(...))
+func RunForm(t testingi.T, form *huh.Form, responder *Responder, opts ...RunOption) error {
+	t.Helper()
+
+	return runForm(t, form, responder, opts...)
+}
+
+// AssertFormAborted runs the form like RunForm and fails the test if the form didn't return huh.ErrUserAborted,
+// which is the case if the responder aborted it using AbortAt. Questions that come up after the form was aborted
+// aren't answered and fail the test as well. Returns whether the assertion succeeded.
+//
+// Usage:
+//
+//	huhtest.AssertFormAborted(t, myForm, huhtest.NewResponder().
+//	  AddResponse(...).
+//	  AbortAt(...))
+func AssertFormAborted(t testingi.T, form *huh.Form, responder *Responder, opts ...RunOption) bool {
+	t.Helper()
+
+	err := runForm(t, form, responder, opts...)
+
+	if !errors.Is(err, huh.ErrUserAborted) {
+		t.Errorf("Form should have returned %q, but returned %v", huh.ErrUserAborted, err)
+		return false
+	}
+
+	return !t.Failed()
+}
+
+// runForm runs the form with the responder answering its questions, see RunForm
+func runForm(t testingi.T, form *huh.Form, responder *Responder, opts ...RunOption) error {
+	t.Helper()
+
+	options := runOptions{
+		ctx:     context.Background(),
+		timeout: defaultRunTimeout,
+	}
+
+	for _, opt := range opts {
+		opt(&options)
+	}
+
+	ctx, cancel := context.WithTimeout(options.ctx, options.timeout)
+	defer cancel()
+
+	stdin, stdout, closer := responder.StartContext(ctx, t)
+
+	err := form.WithInput(stdin).WithOutput(stdout).RunWithContext(ctx)
+
+	// Stop the responder before returning, so the transcript is complete
+	closer()
+
+	return err
+}
+
+// RunField runs a single field with the responder answering it, like huh.Field.Run would. See RunForm.
+func RunField(t testingi.T, field huh.Field, responder *Responder, opts ...RunOption) error {
+	t.Helper()
+
+	form := huh.NewForm(huh.NewGroup(field)).WithShowHelp(false)
+
+	return RunForm(t, form, responder, opts...)
+}
@@ -0,0 +1,150 @@
+package huhtest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/huh"
+	testingi "github.com/mitchellh/go-testing-interface"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRunForm_RunsFormWithResponder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	var (
+		name  string
+		ready bool
+	)
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?").Value(&name),
+			huh.NewConfirm().Title("Are you ready?").Value(&ready),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		AddConfirm("Are you ready?", ConfirmAffirm).
+		GuessConfirms()
+
+	// Act
+	err := RunForm(t, form, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", name)
+	assert.True(t, ready)
+}
+
+func TestRunForm_FailsTestOnTimeout(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?"),
+		),
+	)
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, form, NewResponder(), WithTimeout(100*time.Millisecond))
+
+	// Assert
+	require.Error(t, err)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
+
+func TestRunForm_StopsWhenContextIsCancelled(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?"),
+		),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	err := RunForm(dummyT, form, NewResponder(), WithContext(ctx), WithTimeout(time.Minute))
+
+	// Assert
+	require.Error(t, err)
+	assert.False(t, dummyT.Failed(), "Test should not have failed")
+}
+
+func TestRunField_RunsFieldWithResponder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	var name string
+
+	field := huh.NewInput().Title("What's your name?").Value(&name)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher")
+
+	// Act
+	err := RunField(t, field, responder)
+
+	// Assert
+	require.NoError(t, err)
+
+	assert.Equal(t, "Gopher", name)
+}
+
+func TestAssertFormAborted_SucceedsIfResponderAbortsForm(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	var name string
+
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?").Value(&name),
+		),
+		huh.NewGroup(
+			huh.NewConfirm().Title("Are you ready?"),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher").
+		AbortAt("Are you ready?")
+
+	// Act
+	result := AssertFormAborted(t, form, responder)
+
+	// Assert
+	assert.True(t, result)
+	assert.Equal(t, "Gopher", name)
+}
+
+func TestAssertFormAborted_FailsTestIfFormIsNotAborted(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	form := huh.NewForm(
+		huh.NewGroup(
+			huh.NewInput().Title("What's your name?"),
+		),
+	)
+
+	responder := NewResponder().
+		AddResponse("What's your name?", "Gopher")
+
+	dummyT := new(testingi.RuntimeT)
+
+	// Act
+	result := AssertFormAborted(dummyT, form, responder)
+
+	// Assert
+	assert.False(t, result)
+	assert.True(t, dummyT.Failed(), "Test should have failed")
+}
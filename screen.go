@@ -0,0 +1,577 @@
+package huhtest
+
+import (
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// focusedBorder is the left border that huh's themes render in front of every line of the focused field.
+const focusedBorder = "┃"
+
+// errorIndicator is rendered after the title of some fields while they show a validation error, and in front
+// of the validation errors that huh shows in place of the help text.
+const errorIndicator = " *"
+
+// notePadding is rendered in front of every line of a note, blurred fields are indented further as they're
+// rendered with a hidden border in front of the padding.
+const notePadding = " "
+
+const (
+	// hideCursor and showCursor are the parameters and final byte of the sequences that bubbletea sends when
+	// a program starts and stops, as the cursor is hidden while it's running.
+	hideCursor = "?25l"
+	showCursor = "?25h"
+)
+
+// screen is a minimal terminal emulator that keeps track of the output of a form. Bubbletea redraws a form
+// by moving the cursor around and erasing lines, so looking at the output line-by-line doesn't tell us what
+// the user would actually see. It implements io.Writer, so output can be written to it directly.
+//
+// Only the escape sequences that are used by bubbletea to render inline forms are interpreted, others are ignored.
+type screen struct {
+	rows [][]rune
+
+	// styles contain the style of every character in rows, see style
+	styles [][]string
+
+	// style contains the parameters of the latest select graphic rendition sequence, which styles characters
+	// that are printed. Lipgloss resets the style after every piece of styled text, so there's no need to combine them.
+	style string
+
+	// row and column of the cursor, starting at 0
+	row    int
+	column int
+
+	// pending contains the start of an escape sequence or character that was cut off at the end of a write
+	pending []byte
+
+	// changed contains the rows that were written to since the last call to changedLines
+	changed map[int]struct{}
+
+	// programTop is the row the cursor was on when a bubbletea program started, or -1 if none is running
+	programTop int
+}
+
+// newScreen returns an empty screen with the cursor in the top left corner.
+func newScreen() *screen {
+	return &screen{
+		rows:       [][]rune{nil},
+		styles:     [][]string{nil},
+		changed:    make(map[int]struct{}),
+		programTop: -1,
+	}
+}
+
+// Write interprets the given output, it never returns an error.
+func (s *screen) Write(output []byte) (int, error) {
+	data := append(s.pending, output...)
+	s.pending = nil
+
+	for len(data) > 0 {
+		consumed := s.interpret(data)
+		if consumed == 0 {
+			s.pending = append([]byte{}, data...)
+			break
+		}
+
+		data = data[consumed:]
+	}
+
+	return len(output), nil
+}
+
+// interpret handles the first character or escape sequence in data and returns the amount of bytes it used,
+// or 0 if the data ends before the character or escape sequence is complete.
+func (s *screen) interpret(data []byte) int {
+	switch data[0] {
+	case '\x1b':
+		return s.escape(data)
+	case '\r':
+		s.column = 0
+	case '\n':
+		s.moveTo(s.row+1, s.column)
+	case '\b':
+		s.moveTo(s.row, s.column-1)
+	case '\t':
+		s.moveTo(s.row, (s.column/8+1)*8)
+	default:
+		if data[0] < 0x20 || data[0] == 0x7f {
+			return 1
+		}
+
+		if !utf8.FullRune(data) {
+			return 0
+		}
+
+		character, size := utf8.DecodeRune(data)
+		s.print(character)
+
+		return size
+	}
+
+	return 1
+}
+
+// escape handles the escape sequence at the start of data, see interpret.
+func (s *screen) escape(data []byte) int {
+	if len(data) < 2 {
+		return 0
+	}
+
+	switch data[1] {
+	case '[':
+		// Control sequence, parameters and intermediate bytes are followed by a final byte
+		for index := 2; index < len(data); index++ {
+			if data[index] >= 0x40 && data[index] <= 0x7e {
+				s.control(string(data[2:index]), data[index])
+				return index + 1
+			}
+		}
+
+		return 0
+
+	case ']':
+		// Operating system command, terminated by BEL or ESC \
+		for index := 2; index < len(data); index++ {
+			if data[index] == '\x07' {
+				return index + 1
+			}
+
+			if data[index] == '\x1b' && index+1 < len(data) && data[index+1] == '\\' {
+				return index + 2
+			}
+		}
+
+		return 0
+
+	default:
+		return 2
+	}
+}
+
+// control executes a control sequence with the given parameters and final byte.
+func (s *screen) control(parameters string, final byte) {
+	// Private sequences like showing and hiding the cursor don't affect what's on the screen, but they
+	// do tell us where the output of a bubbletea program starts
+	if strings.HasPrefix(parameters, "?") {
+		switch parameters + string(final) {
+		case hideCursor:
+			if s.programTop == -1 {
+				s.programTop = s.row
+			}
+		case showCursor:
+			s.programTop = -1
+		}
+
+		return
+	}
+
+	values := strings.Split(parameters, ";")
+
+	// parameter returns the numeric parameter at the given index, or the fallback if it's missing or 0
+	parameter := func(index int, fallback int) int {
+		if index >= len(values) {
+			return fallback
+		}
+
+		value, err := strconv.Atoi(values[index])
+		if err != nil || value == 0 {
+			return fallback
+		}
+
+		return value
+	}
+
+	switch final {
+	case 'A':
+		s.moveTo(s.row-parameter(0, 1), s.column)
+	case 'B':
+		s.moveTo(s.row+parameter(0, 1), s.column)
+	case 'C':
+		s.moveTo(s.row, s.column+parameter(0, 1))
+	case 'D':
+		// Bubbletea moves the cursor back by the width of the terminal to return to the start of the line,
+		// but it leaves out the amount if it doesn't know the width, like when rendering to a pipe.
+		if parameters == "" {
+			s.moveTo(s.row, 0)
+			break
+		}
+
+		s.moveTo(s.row, s.column-parameter(0, 1))
+	case 'E':
+		s.moveTo(s.row+parameter(0, 1), 0)
+	case 'F':
+		s.moveTo(s.row-parameter(0, 1), 0)
+	case 'G':
+		s.moveTo(s.row, parameter(0, 1)-1)
+	case 'H', 'f':
+		s.moveTo(parameter(0, 1)-1, parameter(1, 1)-1)
+	case 'J':
+		s.eraseDisplay(parameter(0, 0))
+	case 'K':
+		s.eraseLine(s.row, parameter(0, 0))
+	case 'm':
+		s.style = strings.TrimPrefix(strings.TrimPrefix(parameters, "0"), ";")
+	}
+}
+
+// moveTo moves the cursor to the given position, it can't go beyond the top or left of the screen.
+func (s *screen) moveTo(row int, column int) {
+	s.row = max(row, 0)
+	s.column = max(column, 0)
+
+	for len(s.rows) <= s.row {
+		s.rows = append(s.rows, nil)
+		s.styles = append(s.styles, nil)
+	}
+}
+
+// print puts a character at the cursor and moves the cursor to the right.
+func (s *screen) print(character rune) {
+	row := s.rows[s.row]
+	styles := s.styles[s.row]
+
+	for len(row) <= s.column {
+		row = append(row, ' ')
+	}
+
+	for len(styles) <= s.column {
+		styles = append(styles, "")
+	}
+
+	row[s.column] = character
+	styles[s.column] = s.style
+
+	s.rows[s.row] = row
+	s.styles[s.row] = styles
+	s.changed[s.row] = struct{}{}
+	s.column++
+}
+
+// eraseLine clears (a part of) a row, 0 clears from the cursor to the end, 1 from the start to the cursor
+// and 2 clears the whole row.
+func (s *screen) eraseLine(row int, mode int) {
+	line := s.rows[row]
+	styles := s.styles[row]
+
+	switch mode {
+	case 0:
+		line = line[:min(s.column, len(line))]
+		styles = styles[:min(s.column, len(styles))]
+	case 1:
+		for index := range min(s.column+1, len(line)) {
+			line[index] = ' '
+			styles[index] = ""
+		}
+	case 2:
+		line = nil
+		styles = nil
+	}
+
+	s.rows[row] = line
+	s.styles[row] = styles
+	s.changed[row] = struct{}{}
+}
+
+// eraseDisplay clears (a part of) the screen, using the same modes as eraseLine.
+func (s *screen) eraseDisplay(mode int) {
+	switch mode {
+	case 0:
+		s.eraseLine(s.row, 0)
+
+		for row := s.row + 1; row < len(s.rows); row++ {
+			s.eraseLine(row, 2)
+		}
+	case 1:
+		for row := range s.row {
+			s.eraseLine(row, 2)
+		}
+
+		s.eraseLine(s.row, 1)
+	case 2:
+		for row := range s.rows {
+			s.eraseLine(row, 2)
+		}
+	}
+}
+
+// lines returns all rows on the screen without trailing whitespace, trailing empty rows are left out.
+func (s *screen) lines() []string {
+	result := make([]string, len(s.rows))
+
+	for index, row := range s.rows {
+		result[index] = strings.TrimRight(string(row), " ")
+	}
+
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+
+	return result
+}
+
+// fieldStyles returns the styles of the characters on the given amount of rows, starting at the top row. A style
+// consists of the parameters of the select graphic rendition sequence it was printed with, or is empty if it had none.
+func (s *screen) fieldStyles(top int, rows int) [][]string {
+	result := make([][]string, rows)
+
+	for index := range result {
+		if row := top + index; row >= 0 && row < len(s.styles) {
+			result[index] = slices.Clone(s.styles[row])
+		}
+	}
+
+	return result
+}
+
+// String returns the screen as it would be displayed.
+func (s *screen) String() string {
+	return strings.Join(s.lines(), "\n")
+}
+
+// styledString returns the screen like String, but with the style of every character as a select graphic rendition
+// sequence in front of it. Every line ends unstyled, so lines can be compared on their own.
+func (s *screen) styledString() string {
+	result := make([]string, len(s.rows))
+
+	for index, row := range s.rows {
+		styles := s.styles[index]
+
+		// Trailing whitespace is left out like in lines, unless it has a style that makes it visible
+		end := len(row)
+		for end > 0 && row[end-1] == ' ' && (end > len(styles) || styles[end-1] == "") {
+			end--
+		}
+
+		var line strings.Builder
+		var style string
+
+		for column, character := range row[:end] {
+			var next string
+			if column < len(styles) {
+				next = styles[column]
+			}
+
+			if next != style {
+				line.WriteString("\x1b[" + next + "m")
+				style = next
+			}
+
+			line.WriteRune(character)
+		}
+
+		if style != "" {
+			line.WriteString("\x1b[m")
+		}
+
+		result[index] = line.String()
+	}
+
+	for len(result) > 0 && result[len(result)-1] == "" {
+		result = result[:len(result)-1]
+	}
+
+	return strings.Join(result, "\n")
+}
+
+// changedLines returns the non-empty rows that have been written to since the last call, in order.
+func (s *screen) changedLines() []string {
+	lines := s.lines()
+	result := make([]string, 0, len(s.changed))
+
+	for index, line := range lines {
+		if _, ok := s.changed[index]; ok && line != "" {
+			result = append(result, line)
+		}
+	}
+
+	clear(s.changed)
+
+	return result
+}
+
+// focusedField returns the lines of the field that has focus, which are the lines that start with the
+// focusedBorder. The first line is usually the title. It also returns the row the field starts at. If
+// multiple fields appear to be focused, the one closest to the bottom is returned as it was rendered last.
+func (s *screen) focusedField() ([]string, int, bool) {
+	lines := s.lines()
+
+	start := -1
+	var result []string
+
+	for index, line := range lines {
+		if !strings.HasPrefix(line, focusedBorder) {
+			continue
+		}
+
+		// A new field starts if the previous line didn't belong to a field
+		if index == 0 || !strings.HasPrefix(lines[index-1], focusedBorder) {
+			start = index
+			result = nil
+		}
+
+		result = append(result, line)
+	}
+
+	return result, start, start != -1
+}
+
+// fieldView is a focused field as it's rendered, answers that depend on what a field looks like are resolved using it
+type fieldView struct {
+	lines []string
+
+	// styles contain the styles of the characters in lines, see fieldStyles
+	styles [][]string
+
+	// note is set if the lines belong to a focused note, see focusedNote
+	note bool
+}
+
+// focusedNote returns the non-empty lines that a running bubbletea program has rendered if none of them belong to a
+// focused field and the first one belongs to a note, along with the row of the first line. That's what a focused
+// huh.Note looks like, as notes are rendered without the focusedBorder. Notes only get focus if they're the only
+// field in their group, when moving between groups the blurred fields of a group are rendered without focus too.
+func (s *screen) focusedNote() ([]string, int, bool) {
+	if _, _, ok := s.focusedField(); ok || s.programTop == -1 {
+		return nil, 0, false
+	}
+
+	lines := s.lines()
+
+	top := -1
+	var result []string
+
+	for index := s.programTop; index < len(lines); index++ {
+		if lines[index] == "" {
+			continue
+		}
+
+		if top == -1 {
+			top = index
+		}
+
+		result = append(result, lines[index])
+	}
+
+	if top == -1 || !strings.HasPrefix(result[0], notePadding) || strings.HasPrefix(result[0], notePadding+" ") {
+		return nil, 0, false
+	}
+
+	return result, top, true
+}
+
+// validationErrors returns the messages of the validation errors that are shown below the given row.
+func (s *screen) validationErrors(top int) []string {
+	lines := s.lines()
+
+	var result []string
+
+	for index := max(top, 0); index < len(lines); index++ {
+		if message, ok := strings.CutPrefix(lines[index], errorIndicator+" "); ok {
+			result = append(result, message)
+		}
+	}
+
+	return result
+}
+
+// fieldTitle returns the title of a focused field given its first line, without the focusedBorder and errorIndicator.
+// The option that an inline select shows after its title is left out as well, as it changes while the field is answered.
+func fieldTitle(line string) string {
+	if index := strings.LastIndex(line, prevIndicator); index != -1 && strings.HasSuffix(line, nextIndicator) {
+		line = line[:index]
+	}
+
+	return strings.TrimSuffix(strings.TrimPrefix(line, focusedBorder+" "), errorIndicator)
+}
+
+// fieldBody returns the lines of a focused field that follow its title, without the focusedBorder and the padding
+// after it. Lines that are too short to contain anything after the border are left out.
+func fieldBody(field []string) []string {
+	var result []string
+
+	for _, line := range field[min(1, len(field)):] {
+		runes := []rune(line)
+		if len(runes) < 2 {
+			continue
+		}
+
+		result = append(result, string(runes[2:]))
+	}
+
+	return result
+}
+
+// answeredField is the focused field as it was when it was answered, used to determine whether a focused field
+// is asking a question that hasn't been answered yet.
+type answeredField struct {
+	top   int
+	lines []string
+
+	// title is the title of the field, which a select replaces by its filter while filtering
+	title string
+
+	// styles are compared as well, as toggling a confirm field only changes the colours of its buttons
+	styles [][]string
+
+	// rendered contains the lines of the whole screen
+	rendered []string
+
+	// changed is set once the field has been rendered in a different state, which shows that the answer arrived
+	changed bool
+}
+
+// asks returns true if the focused field with the given view and top row is a new question. That's the case if it's
+// a different field, which means that our answer has been submitted and the focus moved on. The same field is only
+// asked again, like in consecutive groups, once the form has moved on to a screen we haven't seen while answering:
+// the field is in the state it was in when we answered it, but the rest of the screen differs.
+//
+// Frames that show the whole screen as it was when we answered belong to our own answer, like a value that's typed
+// again after it was cleared. Before our answer has been shown, fields that change on their own, like a select that's
+// loading its options, cause frames in which our answer hasn't arrived yet, so only a different help at the bottom
+// shows that the form moved on then.
+//
+// Calling it on a nil answeredField always returns true, as nothing has been answered yet.
+func (a *answeredField) asks(field fieldView, top int, rendered []string) bool {
+	if !a.sameField(field.lines, top) {
+		return true
+	}
+
+	if !slices.Equal(a.lines, field.lines) || !slices.EqualFunc(a.styles, field.styles, slices.Equal) {
+		a.changed = true
+		return false
+	}
+
+	if slices.Equal(a.rendered, rendered) {
+		return false
+	}
+
+	return a.changed || slices.Equal(withoutHelp(a.rendered), withoutHelp(rendered))
+}
+
+// newAnsweredField returns the answeredField of a focused field that's answered in the given view, see asks.
+func newAnsweredField(top int, field fieldView, rendered []string) *answeredField {
+	return &answeredField{top: top, lines: field.lines, title: fieldTitle(field.lines[0]), styles: field.styles, rendered: rendered}
+}
+
+// withoutHelp returns the lines of the screen without the last one, which shows the help of the focused field
+func withoutHelp(rendered []string) []string {
+	return rendered[:max(len(rendered)-1, 0)]
+}
+
+// sameField returns true if the focused field with the given lines and top row is the field that was answered,
+// regardless of its state. Calling it on a nil answeredField always returns false.
+//
+// A select or multi-select shows its filter in place of the title while filtering, and after the title once the
+// filter has been set, so those titles belong to the answered field as well.
+func (a *answeredField) sameField(lines []string, top int) bool {
+	if a == nil || a.top != top {
+		return false
+	}
+
+	title := fieldTitle(lines[0])
+
+	return title == a.title || strings.HasPrefix(title, filterOptions) || strings.HasPrefix(title, a.title+filterOptions)
+}
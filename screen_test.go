@@ -0,0 +1,597 @@
+package huhtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreen_Write_RendersExpectedScreen(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output []string
+
+		expected string
+	}{
+		"plain lines": {
+			output:   []string{"Hello\r\nWorld\r\n"},
+			expected: "Hello\nWorld",
+		},
+		"carriage return overwrites": {
+			output:   []string{"Hello\rJ"},
+			expected: "Jello",
+		},
+		"cursor movement": {
+			output:   []string{"a\r\nb\r\nc\x1b[2A\x1b[Dd\x1b[B\x1b[2Ce"},
+			expected: "d\nb  e\nc",
+		},
+		"absolute cursor movement": {
+			output:   []string{"abc\x1b[2;2Hd\x1b[1Ge"},
+			expected: "abc\ned",
+		},
+		"erase line": {
+			output:   []string{"Hello\x1b[2K\x1b[DBye"},
+			expected: "Bye",
+		},
+		"erase end of line": {
+			output:   []string{"Hello\x1b[3D\x1b[K"},
+			expected: "He",
+		},
+		"erase display": {
+			output:   []string{"a\r\nb\r\nc\x1b[A\x1b[J"},
+			expected: "a\nb",
+		},
+		"ignores styling and private sequences": {
+			output:   []string{"\x1b[?25l\x1b[1;31mred\x1b[0m\x1b]0;title\x07"},
+			expected: "red",
+		},
+		"sequences split across writes": {
+			output:   []string{"a\x1b", "[2", "Kb\xe2\x94", "\x83"},
+			expected: " b┃",
+		},
+		"bubbletea redraw": {
+			output: []string{
+				"\r┃ Are You OK?     \r\n┃ >               \r\n                \r\nenter next\x1b[D",
+				"\x1b[2K\x1b[A\x1b[2K\x1b[A\x1b[A\x1b[D\x1b[2K  Are You OK?\r\n  > yes\r\n\r\n┃ Next?",
+			},
+			expected: "  Are You OK?\n  > yes\n\n┃ Next?",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+
+			// Act
+			for _, output := range testData.output {
+				_, _ = screen.Write([]byte(output))
+			}
+
+			// Assert
+			assert.Equal(t, testData.expected, screen.String())
+		})
+	}
+}
+
+func TestScreen_ChangedLines_ReturnsLinesWrittenToSinceLastCall(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	screen := newScreen()
+
+	_, _ = screen.Write([]byte("a\r\nb\r\nc"))
+	first := screen.changedLines()
+
+	_, _ = screen.Write([]byte("\x1b[Ad"))
+
+	// Act
+	result := screen.changedLines()
+
+	// Assert
+	assert.Equal(t, []string{"a", "b", "c"}, first)
+	assert.Equal(t, []string{"bd"}, result)
+}
+
+func TestScreen_FieldStyles_ReturnsStylesOfCharacters(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+		top    int
+		rows   int
+
+		expected [][]string
+	}{
+		"unstyled": {
+			output:   "ab",
+			rows:     1,
+			expected: [][]string{{"", ""}},
+		},
+		"styled": {
+			output:   "a\x1b[1;31mb\x1b[0mc",
+			rows:     1,
+			expected: [][]string{{"", "1;31", ""}},
+		},
+		"reset with parameters": {
+			output:   "\x1b[0;32ma\x1b[mb",
+			rows:     1,
+			expected: [][]string{{"32", ""}},
+		},
+		"overwritten": {
+			output:   "\x1b[31mab\x1b[0m\rc",
+			rows:     1,
+			expected: [][]string{{"", "31"}},
+		},
+		"erased": {
+			output:   "\x1b[31ma\x1b[0m\r\n\x1b[31mb\x1b[2K",
+			rows:     2,
+			expected: [][]string{{"31"}, nil},
+		},
+		"rows below the screen": {
+			output:   "a\r\n\x1b[31mb",
+			top:      1,
+			rows:     2,
+			expected: [][]string{{"31"}, nil},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+			_, _ = screen.Write([]byte(testData.output))
+
+			// Act
+			result := screen.fieldStyles(testData.top, testData.rows)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestScreen_StyledString_ReturnsScreenWithStyles(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+
+		expected string
+	}{
+		"unstyled": {
+			output:   "ab  \r\n\r\n",
+			expected: "ab",
+		},
+		"styled": {
+			output:   "a\x1b[1;31mbc\x1b[0md",
+			expected: "a\x1b[1;31mbc\x1b[md",
+		},
+		"styled until the end of the line": {
+			output:   "a\x1b[32mb\r\nc",
+			expected: "a\x1b[32mb\x1b[m\n\x1b[32mc\x1b[m",
+		},
+		"styled trailing whitespace": {
+			output:   "a\x1b[41m  \x1b[0m  ",
+			expected: "a\x1b[41m  \x1b[m",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+			_, _ = screen.Write([]byte(testData.output))
+
+			// Act
+			result := screen.styledString()
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestScreen_FocusedField_ReturnsFocusedField(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+
+		expectedLines []string
+		expectedTop   int
+		expectedOk    bool
+	}{
+		"no focused field": {
+			output: "  Title\r\n  >\r\n",
+		},
+		"focused field": {
+			output:        "  A?\r\n  > a\r\n\r\n┃ B?\r\n┃ >\r\n\r\n  C?\r\n  >",
+			expectedLines: []string{"┃ B?", "┃ >"},
+			expectedTop:   3,
+			expectedOk:    true,
+		},
+		"last focused field": {
+			output:        "┃ A?\r\n┃ > a\r\n\r\n┃ B?\r\n┃ >",
+			expectedLines: []string{"┃ B?", "┃ >"},
+			expectedTop:   3,
+			expectedOk:    true,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+			_, _ = screen.Write([]byte(testData.output))
+
+			// Act
+			lines, top, ok := screen.focusedField()
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+
+			if ok {
+				assert.Equal(t, testData.expectedLines, lines)
+				assert.Equal(t, testData.expectedTop, top)
+			}
+		})
+	}
+}
+
+func TestScreen_FocusedNote_ReturnsLinesOfRunningProgram(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+
+		expectedLines []string
+		expectedTop   int
+		expectedOk    bool
+	}{
+		"no program": {
+			output: " Welcome\r\n\r\n Some text",
+		},
+		"program without output": {
+			output: "Output\r\n\x1b[?25l",
+		},
+		"note": {
+			output:        "Output\r\n\x1b[?25l Welcome\r\n\r\n Some text\r\n\r\nenter next",
+			expectedLines: []string{" Welcome", " Some text", "enter next"},
+			expectedTop:   1,
+			expectedOk:    true,
+		},
+		"note after empty lines": {
+			output:        "\x1b[?25l\r\n\r\n Welcome",
+			expectedLines: []string{" Welcome"},
+			expectedTop:   2,
+			expectedOk:    true,
+		},
+		"focused field": {
+			output: "\x1b[?25l Welcome\r\n\r\n┃ A?\r\n┃ >",
+		},
+		"blurred field": {
+			output: "\x1b[?25l  A?\r\n  > a",
+		},
+		"stopped program": {
+			output: "\x1b[?25l Welcome\r\n\x1b[?25h",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+			_, _ = screen.Write([]byte(testData.output))
+
+			// Act
+			lines, top, ok := screen.focusedNote()
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+
+			if ok {
+				assert.Equal(t, testData.expectedLines, lines)
+				assert.Equal(t, testData.expectedTop, top)
+			}
+		})
+	}
+}
+
+func TestScreen_ValidationErrors_ReturnsErrorsBelowRow(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		output string
+		top    int
+
+		expected []string
+	}{
+		"no errors": {
+			output: "┃ A?\r\n┃ > a\r\n\r\nenter next",
+		},
+		"error": {
+			output:   "┃ A?\r\n┃ > a\r\n\r\n * too short",
+			expected: []string{"too short"},
+		},
+		"error above row": {
+			output: " * too short\r\n\r\n┃ A?\r\n┃ > a",
+			top:    2,
+		},
+		"errors": {
+			output:   "┃ A? *\r\n┃ > a\r\n\r\n * too short\r\n * too long",
+			expected: []string{"too short", "too long"},
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Arrange
+			screen := newScreen()
+			_, _ = screen.Write([]byte(testData.output))
+
+			// Act
+			result := screen.validationErrors(testData.top)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestFieldTitle_ReturnsTitleWithoutDecoration(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		line string
+
+		expected string
+	}{
+		"title":                    {line: "┃ A?", expected: "A?"},
+		"title with error":         {line: "┃ A? *", expected: "A?"},
+		"title of line-based form": {line: "A?", expected: "A?"},
+		"title of inline select":   {line: "┃ A? *← a →", expected: "A?"},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := fieldTitle(testData.line)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestFieldBody_ReturnsLinesAfterTitleWithoutBorder(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected []string
+	}{
+		"options":          {field: []string{"┃ A?", "┃ > a", "┃   b"}, expected: []string{"> a", "  b"}},
+		"empty lines":      {field: []string{"┃ A?", "┃", "┃ > a"}, expected: []string{"> a"}},
+		"only a title":     {field: []string{"┃ A?"}, expected: nil},
+		"nothing rendered": {field: []string{}, expected: nil},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := fieldBody(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestAnsweredField_Asks_ReturnsWhetherFieldIsNewQuestion(t *testing.T) {
+	t.Parallel()
+
+	rendered := []string{"", "", "┃ A?", "┃ >", "", "  B?", "  > a", "", "enter next"}
+
+	// Every test gets its own answeredField, as asks remembers whether the answer arrived
+	answered := func(changed bool) *answeredField {
+		return &answeredField{
+			top:      2,
+			lines:    []string{"┃ A?", "┃ >"},
+			title:    "A?",
+			styles:   [][]string{nil, {"", "31"}},
+			rendered: rendered,
+			changed:  changed,
+		}
+	}
+
+	tests := map[string]struct {
+		answered *answeredField
+		field    fieldView
+		top      int
+		rendered []string
+
+		expected        bool
+		expectedChanged bool
+	}{
+		"nothing answered yet": {
+			field:    fieldView{lines: []string{"┃ A?", "┃ >"}},
+			top:      2,
+			rendered: rendered,
+			expected: true,
+		},
+		"different title": {
+			answered: answered(false),
+			field:    fieldView{lines: []string{"┃ B?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:      2,
+			rendered: rendered,
+			expected: true,
+		},
+		"different position": {
+			answered: answered(false),
+			field:    fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:      5,
+			rendered: rendered,
+			expected: true,
+		},
+		"answer being typed": {
+			answered:        answered(false),
+			field:           fieldView{lines: []string{"┃ A?", "┃ > ye"}, styles: [][]string{nil, {"", "31"}}},
+			top:             2,
+			rendered:        rendered,
+			expected:        false,
+			expectedChanged: true,
+		},
+		"different colours": {
+			answered:        answered(false),
+			field:           fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "32"}}},
+			top:             2,
+			rendered:        rendered,
+			expected:        false,
+			expectedChanged: true,
+		},
+		"other field changed before the answer arrived": {
+			answered: answered(false),
+			field:    fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:      2,
+			rendered: []string{"", "", "┃ A?", "┃ >", "", "  B?", "  - Loading...", "", "enter next"},
+			expected: false,
+		},
+		"same question on the same screen": {
+			answered: answered(false),
+			field:    fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:      2,
+			rendered: rendered,
+			expected: false,
+		},
+		"same question on the same screen after the answer arrived": {
+			answered:        answered(true),
+			field:           fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:             2,
+			rendered:        rendered,
+			expected:        false,
+			expectedChanged: true,
+		},
+		"same question with different help": {
+			answered: answered(false),
+			field:    fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:      2,
+			rendered: []string{"", "", "┃ A?", "┃ >", "", "  B?", "  > a", "", "enter submit"},
+			expected: true,
+		},
+		"same question after the answer arrived": {
+			answered:        answered(true),
+			field:           fieldView{lines: []string{"┃ A?", "┃ >"}, styles: [][]string{nil, {"", "31"}}},
+			top:             2,
+			rendered:        []string{"", "", "┃ A?", "┃ >", "", "  C?", "", "enter next"},
+			expected:        true,
+			expectedChanged: true,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.answered.asks(testData.field, testData.top, testData.rendered)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+
+			if testData.answered != nil {
+				assert.Equal(t, testData.expectedChanged, testData.answered.changed)
+			}
+		})
+	}
+}
+
+func TestAnsweredField_SameField_ReturnsWhetherFieldWasAnswered(t *testing.T) {
+	t.Parallel()
+
+	answered := newAnsweredField(2, fieldView{lines: []string{"┃ A?", "┃ >"}}, nil)
+
+	// A select that's answered by filtering is recorded with the title it was answered for
+	filtered := &answeredField{top: 2, lines: []string{"┃ /ye", "┃ > yes"}, title: "A?"}
+
+	tests := map[string]struct {
+		answered *answeredField
+		lines    []string
+		top      int
+
+		expected bool
+	}{
+		"nothing answered yet": {
+			lines:    []string{"┃ A?", "┃ >"},
+			top:      2,
+			expected: false,
+		},
+		"different title": {
+			answered: answered,
+			lines:    []string{"┃ B?", "┃ >"},
+			top:      2,
+			expected: false,
+		},
+		"different position": {
+			answered: answered,
+			lines:    []string{"┃ A?", "┃ >"},
+			top:      5,
+			expected: false,
+		},
+		"different state": {
+			answered: answered,
+			lines:    []string{"┃ A?", "┃ > ye"},
+			top:      2,
+			expected: true,
+		},
+		"validation error": {
+			answered: answered,
+			lines:    []string{"┃ A? *", "┃ > ye"},
+			top:      2,
+			expected: true,
+		},
+		"filtering": {
+			answered: answered,
+			lines:    []string{"┃ /ye", "┃ > yes"},
+			top:      2,
+			expected: true,
+		},
+		"filter set": {
+			answered: answered,
+			lines:    []string{"┃ A?/ye", "┃ > yes"},
+			top:      2,
+			expected: true,
+		},
+		"filter set after filtering": {
+			answered: filtered,
+			lines:    []string{"┃ A?/ye", "┃ > yes"},
+			top:      2,
+			expected: true,
+		},
+		"different title after filtering": {
+			answered: filtered,
+			lines:    []string{"┃ B?", "┃ >"},
+			top:      2,
+			expected: false,
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.answered.sameField(testData.lines, testData.top)
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+package huhtest
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// inputPrompt is rendered in front of the value of an input, it's the default prompt of a bubbles text input
+const inputPrompt = "> "
+
+// inputValue returns the value of an input as it's rendered on the last line of the field, after the inputPrompt. It
+// returns false if the prompt can't be found, like when a form is answered line by line.
+func inputValue(field []string) (string, bool) {
+	if len(field) == 0 {
+		return "", false
+	}
+
+	line := strings.TrimPrefix(field[len(field)-1], focusedBorder+" ")
+
+	// Trailing spaces are trimmed from the screen, including the one of the prompt if the input is empty
+	_, value, ok := strings.Cut(line+" ", inputPrompt)
+
+	return strings.TrimRight(value, " "), ok
+}
+
+// errUnexpectedSuggestion is returned if accepting the suggestion of an input results in a different value than expected
+var errUnexpectedSuggestion = errors.New("suggestion resulted in an unexpected value")
+
+// suggestionPick types a prefix into an input and accepts the suggestion that the input shows for it, the field is
+// only submitted if the value that's shown afterwards is the expected completion.
+type suggestionPick struct {
+	prefix     string
+	completion string
+
+	// typed is set once the keys to type the prefix and accept the suggestion have been sent
+	typed bool
+}
+
+// newSuggestionPick returns a suggestionPick for the prefix and completion that joinSuggestion stored in the answer.
+func newSuggestionPick(answer string) *suggestionPick {
+	prefix, completion, _ := strings.Cut(answer, labelSeparator)
+
+	return &suggestionPick{prefix: prefix, completion: completion}
+}
+
+// joinSuggestion stores a prefix and the completion that's expected for it in a single answer.
+func joinSuggestion(prefix string, completion string) string {
+	return prefix + labelSeparator + completion
+}
+
+// next types the prefix and accepts the suggestion, and checks the value once the input shows the prefix. The
+// suggestion is shown after the prefix before it's accepted as well, so a value that doesn't match won't change.
+func (p *suggestionPick) next(field []string) (string, bool, error) {
+	if !p.typed {
+		p.typed = true
+
+		return typeText(p.prefix) + acceptSuggestion, false, nil
+	}
+
+	value, ok := inputValue(field)
+	if !ok || !strings.HasPrefix(value, p.prefix) {
+		return "", false, nil
+	}
+
+	if value != p.completion {
+		return "", false, fmt.Errorf("%q: %w, expected %q but got %q", p.prefix, errUnexpectedSuggestion, p.completion, value)
+	}
+
+	return defaultSubmit, true, nil
+}
@@ -0,0 +1,78 @@
+package huhtest
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputValue_ReturnsRenderedValue(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		field []string
+
+		expected   string
+		expectedOk bool
+	}{
+		"input":            {field: []string{"┃ Title", "┃ > huh  "}, expected: "huh", expectedOk: true},
+		"inline input":     {field: []string{"┃ Title> huh"}, expected: "huh", expectedOk: true},
+		"empty input":      {field: []string{"┃ Title", "┃ >"}, expected: "", expectedOk: true},
+		"line by line":     {field: []string{"Title"}, expected: "", expectedOk: false},
+		"nothing rendered": {field: []string{}, expected: "", expectedOk: false},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result, ok := inputValue(testData.field)
+
+			// Assert
+			assert.Equal(t, testData.expectedOk, ok)
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestSuggestionPick_Next_ReturnsExpectedSteps(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	pick := newSuggestionPick(joinSuggestion("bub", "bubbletea"))
+
+	// Act
+	typed, typedLast, typedErr := pick.next([]string{"┃ Title", "┃ >"})
+	waiting, waitingLast, waitingErr := pick.next([]string{"┃ Title", "┃ > b"})
+	submit, submitLast, submitErr := pick.next([]string{"┃ Title", "┃ > bubbletea"})
+
+	// Assert
+	require.NoError(t, typedErr)
+	require.NoError(t, waitingErr)
+	require.NoError(t, submitErr)
+
+	assert.Equal(t, "bub<complete>", readableReplacer.Replace(typed))
+	assert.False(t, typedLast)
+
+	assert.Empty(t, waiting)
+	assert.False(t, waitingLast)
+
+	assert.Equal(t, "<submit>", readableReplacer.Replace(submit))
+	assert.True(t, submitLast)
+}
+
+func TestSuggestionPick_Next_ReturnsErrorOnUnexpectedSuggestion(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	pick := newSuggestionPick(joinSuggestion("huh", "huhtest"))
+	_, _, _ = pick.next([]string{"┃ Title", "┃ >"})
+
+	// Act
+	result, last, err := pick.next([]string{"┃ Title", "┃ > huh"})
+
+	// Assert
+	require.ErrorIs(t, err, errUnexpectedSuggestion)
+	assert.EqualError(t, err, `"huh": suggestion resulted in an unexpected value, expected "huhtest" but got "huh"`)
+	assert.Empty(t, result)
+	assert.False(t, last)
+}
@@ -0,0 +1,99 @@
+package huhtest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+)
+
+// transcriptKind describes what an entry in the transcript is about
+type transcriptKind string
+
+const (
+	// transcriptFrame is what the screen looked like after output of the form was received
+	transcriptFrame transcriptKind = "frame"
+
+	// transcriptMatch is a question that matched a response
+	transcriptMatch transcriptKind = "match"
+
+	// transcriptReply is input that was sent to the form, made readable using readableReplacer
+	transcriptReply transcriptKind = "reply"
+
+	// transcriptError is an error that made the test fail
+	transcriptError transcriptKind = "error"
+
+	// transcriptWarning is something that may make the answer differ from what the test expects
+	transcriptWarning transcriptKind = "warning"
+
+	// transcriptLog is anything else the Responder logged
+	transcriptLog transcriptKind = "log"
+)
+
+// transcriptEntry is something the Responder did or saw, along with the time since it was started.
+type transcriptEntry struct {
+	elapsed time.Duration
+	kind    transcriptKind
+	text    string
+}
+
+// String returns the entry prefixed with its timestamp, frames are put on the lines below it.
+func (e transcriptEntry) String() string {
+	timestamp := fmt.Sprintf("[%10s]", e.elapsed.Round(time.Microsecond))
+
+	if e.kind == transcriptFrame {
+		return timestamp + " Received frame:\n" + e.text
+	}
+
+	return timestamp + " " + e.text
+}
+
+// transcript records everything the Responder does after it's started, regardless of Debug. It's written to from
+// the goroutines of the Responder, so it guards its entries.
+type transcript struct {
+	// started is the time the timestamps of the entries are relative to, it's set by the first entry
+	started time.Time
+
+	entries []transcriptEntry
+
+	// lastFrame is the text of the latest frame, frames are only added if they differ from it
+	lastFrame string
+
+	lock sync.Mutex
+}
+
+// add records an entry of the given kind. Frames that look the same as the previous one are left out, as the form
+// may render without changing what's on the screen.
+func (t *transcript) add(kind transcriptKind, text string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	now := time.Now()
+
+	if t.started.IsZero() {
+		t.started = now
+	}
+
+	if kind == transcriptFrame {
+		if text == t.lastFrame {
+			return
+		}
+
+		t.lastFrame = text
+	}
+
+	t.entries = append(t.entries, transcriptEntry{elapsed: now.Sub(t.started), kind: kind, text: text})
+}
+
+// String returns every entry on its own line, in the order they were added.
+func (t *transcript) String() string {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	lines := make([]string, len(t.entries))
+	for index, entry := range t.entries {
+		lines[index] = entry.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,89 @@
+package huhtest
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTranscriptEntry_String_ReturnsExpectedString(t *testing.T) {
+	t.Parallel()
+
+	tests := map[string]struct {
+		entry transcriptEntry
+
+		expected string
+	}{
+		"match": {
+			entry:    transcriptEntry{elapsed: 1500 * time.Microsecond, kind: transcriptMatch, text: "Matches question: a?"},
+			expected: "[     1.5ms] Matches question: a?",
+		},
+		"frame": {
+			entry:    transcriptEntry{elapsed: 2 * time.Second, kind: transcriptFrame, text: "┃ a?\n┃ >"},
+			expected: "[        2s] Received frame:\n┃ a?\n┃ >",
+		},
+		"rounded": {
+			entry:    transcriptEntry{elapsed: 1234567 * time.Nanosecond, kind: transcriptReply, text: "Replying: a<submit>"},
+			expected: "[   1.235ms] Replying: a<submit>",
+		},
+	}
+
+	for name, testData := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Parallel()
+			// Act
+			result := testData.entry.String()
+
+			// Assert
+			assert.Equal(t, testData.expected, result)
+		})
+	}
+}
+
+func TestTranscript_Add_LeavesOutRepeatedFrames(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	var result transcript
+
+	// Act
+	result.add(transcriptFrame, "┃ a?")
+	result.add(transcriptFrame, "┃ a?")
+	result.add(transcriptReply, "Replying: a<submit>")
+	result.add(transcriptFrame, "┃ a?\n┃ > a")
+	result.add(transcriptFrame, "┃ a?")
+
+	// Assert
+	kinds := make([]transcriptKind, len(result.entries))
+	texts := make([]string, len(result.entries))
+
+	for index, entry := range result.entries {
+		kinds[index] = entry.kind
+		texts[index] = entry.text
+	}
+
+	assert.Equal(t, []transcriptKind{transcriptFrame, transcriptReply, transcriptFrame, transcriptFrame}, kinds)
+	assert.Equal(t, []string{"┃ a?", "Replying: a<submit>", "┃ a?\n┃ > a", "┃ a?"}, texts)
+}
+
+func TestTranscript_String_ReturnsEntriesInOrder(t *testing.T) {
+	t.Parallel()
+	// Arrange
+	result := transcript{
+		entries: []transcriptEntry{
+			{elapsed: 0, kind: transcriptLog, text: "Started"},
+			{elapsed: time.Millisecond, kind: transcriptFrame, text: "┃ a?"},
+			{elapsed: 2 * time.Millisecond, kind: transcriptError, text: "something went wrong"},
+		},
+	}
+
+	// Act
+	output := result.String()
+
+	// Assert
+	expected := "[        0s] Started\n" +
+		"[       1ms] Received frame:\n┃ a?\n" +
+		"[       2ms] something went wrong"
+
+	assert.Equal(t, expected, output)
+}